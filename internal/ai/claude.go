@@ -2,7 +2,9 @@ package ai
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
 	"strings"
 
 	anthropic "github.com/anthropics/anthropic-sdk-go"
@@ -30,6 +32,15 @@ func (p *ClaudeProvider) Name() string {
 	return "claude"
 }
 
+func (p *ClaudeProvider) EstimateTokens(text string) int {
+	return estimateTokens(text, 1, 3.5)
+}
+
+// MaxInputTokens leaves room for the response in the 200k context window.
+func (p *ClaudeProvider) MaxInputTokens() int {
+	return 150_000
+}
+
 func (p *ClaudeProvider) Summarize(ctx context.Context, notes string, systemPrompt string) (string, error) {
 	return p.Chat(ctx, []Message{
 		{Role: "system", Content: systemPrompt},
@@ -37,9 +48,52 @@ func (p *ClaudeProvider) Summarize(ctx context.Context, notes string, systemProm
 	})
 }
 
-func (p *ClaudeProvider) GenerateTitle(ctx context.Context, summary string) (string, error) {
+func (p *ClaudeProvider) SummarizeStream(ctx context.Context, notes string, systemPrompt string, w io.Writer) (string, error) {
+	systemBlocks, chatMessages := anthropicMessages([]Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: notes},
+	})
+
+	stream := p.client.Messages.NewStreaming(ctx, anthropic.MessageNewParams{
+		Model:     anthropic.Model(p.model),
+		MaxTokens: 4096,
+		System:    systemBlocks,
+		Messages:  chatMessages,
+	})
+	defer func() { _ = stream.Close() }()
+
+	var sb strings.Builder
+	for stream.Next() {
+		event := stream.Current()
+		if event.Type != "content_block_delta" || event.Delta.Type != "text_delta" {
+			continue
+		}
+		sb.WriteString(event.Delta.Text)
+		if _, err := io.WriteString(w, event.Delta.Text); err != nil {
+			return "", err
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return "", fmt.Errorf("anthropic messages API failed: %w", err)
+	}
+
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return "", fmt.Errorf("anthropic returned no text content")
+	}
+	return text, nil
+}
+
+func (p *ClaudeProvider) SummarizeWithImages(ctx context.Context, notes string, systemPrompt string, images []Image) (string, error) {
 	return p.Chat(ctx, []Message{
-		{Role: "system", Content: titleSystemPrompt},
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: notes, Images: images},
+	})
+}
+
+func (p *ClaudeProvider) GenerateTitle(ctx context.Context, summary string, systemPrompt string) (string, error) {
+	return p.Chat(ctx, []Message{
+		{Role: "system", Content: systemPrompt},
 		{Role: "user", Content: summary},
 	})
 }
@@ -88,7 +142,12 @@ func anthropicMessages(messages []Message) ([]anthropic.TextBlockParam, []anthro
 		case "assistant":
 			out = append(out, anthropic.NewAssistantMessage(anthropic.NewTextBlock(content)))
 		default:
-			out = append(out, anthropic.NewUserMessage(anthropic.NewTextBlock(content)))
+			blocks := make([]anthropic.ContentBlockParamUnion, 0, len(message.Images)+1)
+			for _, image := range message.Images {
+				blocks = append(blocks, anthropic.NewImageBlockBase64(image.MediaType, base64.StdEncoding.EncodeToString(image.Data)))
+			}
+			blocks = append(blocks, anthropic.NewTextBlock(content))
+			out = append(out, anthropic.NewUserMessage(blocks...))
 		}
 	}
 
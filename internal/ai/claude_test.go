@@ -1,9 +1,16 @@
 package ai
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	anthropic "github.com/anthropics/anthropic-sdk-go"
+	anthropicoption "github.com/anthropics/anthropic-sdk-go/option"
 )
 
 func TestAnthropicMessages(t *testing.T) {
@@ -50,3 +57,53 @@ func assertAnthropicMessage(t *testing.T, got anthropic.MessageParam, wantRole a
 		t.Fatalf("text = %q, want %q", got.Content[0].OfText.Text, wantText)
 	}
 }
+
+func TestAnthropicMessagesWithImages(t *testing.T) {
+	_, got := anthropicMessages([]Message{
+		{Role: "user", Content: "notes", Images: []Image{{MediaType: "image/jpeg", Data: []byte("jpg")}}},
+	})
+
+	if len(got) != 1 || len(got[0].Content) != 2 {
+		t.Fatalf("messages = %#v", got)
+	}
+	image := got[0].Content[0].OfImage
+	if image == nil || image.Source.OfBase64 == nil || image.Source.OfBase64.Data != "anBn" || image.Source.OfBase64.MediaType != "image/jpeg" {
+		t.Fatalf("image block = %#v", got[0].Content[0])
+	}
+	if got[0].Content[1].OfText == nil || got[0].Content[1].OfText.Text != "notes" {
+		t.Fatalf("text block = %#v", got[0].Content[1])
+	}
+}
+
+func TestClaudeSummarizeStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range []string{
+			`{"type":"message_start","message":{"id":"m","type":"message","role":"assistant","content":[],"model":"m","usage":{"input_tokens":1,"output_tokens":0}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"朝は"}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"散歩"}}`,
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"message_stop"}`,
+		} {
+			var typ struct{ Type string }
+			_ = json.Unmarshal([]byte(event), &typ)
+			_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typ.Type, event)
+		}
+	}))
+	defer server.Close()
+
+	provider := &ClaudeProvider{
+		client: anthropic.NewClient(anthropicoption.WithAPIKey("key"), anthropicoption.WithBaseURL(server.URL)),
+		model:  "m",
+	}
+
+	var streamed strings.Builder
+	got, err := provider.SummarizeStream(context.Background(), "notes", "system", &streamed)
+	if err != nil {
+		t.Fatalf("SummarizeStream() error = %v", err)
+	}
+	if got != "朝は散歩" || streamed.String() != "朝は散歩" {
+		t.Fatalf("SummarizeStream() = %q, streamed %q", got, streamed.String())
+	}
+}
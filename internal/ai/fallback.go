@@ -0,0 +1,189 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	anthropic "github.com/anthropics/anthropic-sdk-go"
+	openai "github.com/openai/openai-go/v3"
+	"google.golang.org/genai"
+)
+
+// FallbackProvider tries providers in order and moves on to the next one when
+// a call fails in a way another vendor may not (see ShouldFailover).
+type FallbackProvider struct {
+	providers []AIProvider
+	used      string
+
+	// OnFailover, when set, is called before retrying on the next provider.
+	OnFailover func(from, to string, err error)
+}
+
+func NewFallbackProvider(providers ...AIProvider) *FallbackProvider {
+	return &FallbackProvider{providers: providers}
+}
+
+// Name lists the providers of the chain, e.g. "claude,gemini".
+func (p *FallbackProvider) Name() string {
+	names := make([]string, 0, len(p.providers))
+	for _, provider := range p.providers {
+		names = append(names, provider.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+// Used returns the name of the provider that produced the last successful response.
+func (p *FallbackProvider) Used() string {
+	return p.used
+}
+
+func (p *FallbackProvider) Summarize(ctx context.Context, notes string, systemPrompt string) (string, error) {
+	return p.try(func(provider AIProvider) (string, error) {
+		return provider.Summarize(ctx, notes, systemPrompt)
+	})
+}
+
+// SummarizeStream only fails over while nothing has been written to w, so
+// the output is never a mix of two responses.
+func (p *FallbackProvider) SummarizeStream(ctx context.Context, notes string, systemPrompt string, w io.Writer) (string, error) {
+	written := &writeTracker{w: w}
+	return p.try(func(provider AIProvider) (string, error) {
+		text, err := provider.SummarizeStream(ctx, notes, systemPrompt, written)
+		if err != nil && written.n > 0 {
+			return "", partialStreamError{err: err}
+		}
+		return text, err
+	})
+}
+
+func (p *FallbackProvider) SummarizeWithImages(ctx context.Context, notes string, systemPrompt string, images []Image) (string, error) {
+	return p.try(func(provider AIProvider) (string, error) {
+		if mp, ok := provider.(MultimodalProvider); ok {
+			return mp.SummarizeWithImages(ctx, notes, systemPrompt, images)
+		}
+		return provider.Summarize(ctx, notes, systemPrompt)
+	})
+}
+
+func (p *FallbackProvider) GenerateTitle(ctx context.Context, summary string, systemPrompt string) (string, error) {
+	return p.try(func(provider AIProvider) (string, error) {
+		return provider.GenerateTitle(ctx, summary, systemPrompt)
+	})
+}
+
+func (p *FallbackProvider) Chat(ctx context.Context, messages []Message) (string, error) {
+	return p.try(func(provider AIProvider) (string, error) {
+		return provider.Chat(ctx, messages)
+	})
+}
+
+// EstimateTokens returns the highest estimate in the chain.
+func (p *FallbackProvider) EstimateTokens(text string) int {
+	var n int
+	for _, provider := range p.providers {
+		n = max(n, EstimateTokens(provider, text))
+	}
+	return n
+}
+
+// MaxInputTokens returns the smallest budget in the chain, so a prompt that
+// fits the first provider also fits the ones it may fail over to.
+func (p *FallbackProvider) MaxInputTokens() int {
+	if len(p.providers) == 0 {
+		return defaultMaxInputTokens
+	}
+	n := MaxInputTokens(p.providers[0], 0)
+	for _, provider := range p.providers[1:] {
+		n = min(n, MaxInputTokens(provider, 0))
+	}
+	return n
+}
+
+func (p *FallbackProvider) try(call func(AIProvider) (string, error)) (string, error) {
+	var errs []error
+	for i, provider := range p.providers {
+		text, err := call(provider)
+		if err == nil {
+			p.used = provider.Name()
+			return text, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+		if i == len(p.providers)-1 || !ShouldFailover(err) {
+			break
+		}
+		if p.OnFailover != nil {
+			p.OnFailover(provider.Name(), p.providers[i+1].Name(), err)
+		}
+	}
+	return "", errors.Join(errs...)
+}
+
+// partialStreamError is returned when a stream failed after part of the
+// response was written; retrying would duplicate that output.
+type partialStreamError struct {
+	err error
+}
+
+func (e partialStreamError) Error() string {
+	return e.err.Error() + " (after a partial response was written)"
+}
+
+func (e partialStreamError) Unwrap() error {
+	return e.err
+}
+
+// ShouldFailover reports whether err is worth retrying on another provider:
+// rate limits, server errors, authentication errors and network failures.
+// Cancellation and invalid requests are not.
+func ShouldFailover(err error) bool {
+	var partial partialStreamError
+	if errors.As(err, &partial) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if code, ok := apiStatusCode(err); ok {
+		switch {
+		case code == http.StatusUnauthorized, code == http.StatusForbidden,
+			code == http.StatusRequestTimeout, code == http.StatusTooManyRequests,
+			code >= http.StatusInternalServerError:
+			return true
+		default:
+			return false
+		}
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// apiStatusCode extracts the HTTP status code from provider SDK errors.
+func apiStatusCode(err error) (int, bool) {
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		return anthropicErr.StatusCode, true
+	}
+	var openaiErr *openai.Error
+	if errors.As(err, &openaiErr) {
+		return openaiErr.StatusCode, true
+	}
+	var geminiErr genai.APIError
+	if errors.As(err, &geminiErr) {
+		return geminiErr.Code, true
+	}
+	return 0, false
+}
+
+// writeTracker counts the bytes written through it.
+type writeTracker struct {
+	w io.Writer
+	n int
+}
+
+func (t *writeTracker) Write(b []byte) (int, error) {
+	n, err := t.w.Write(b)
+	t.n += n
+	return n, err
+}
@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+func TestFallbackProviderFailsOverOnRateLimit(t *testing.T) {
+	first := &failingProvider{name: "claude", err: genai.APIError{Code: 429, Message: "rate limited"}}
+	second := &failingProvider{name: "gemini", text: "summary"}
+	chain := NewFallbackProvider(first, second)
+
+	var failovers []string
+	chain.OnFailover = func(from, to string, err error) {
+		failovers = append(failovers, from+"->"+to)
+	}
+
+	got, err := chain.Summarize(context.Background(), "notes", "system")
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if got != "summary" || chain.Used() != "gemini" {
+		t.Fatalf("Summarize() = %q via %q", got, chain.Used())
+	}
+	if len(failovers) != 1 || failovers[0] != "claude->gemini" {
+		t.Fatalf("failovers = %v", failovers)
+	}
+	if chain.Name() != "claude,gemini" {
+		t.Fatalf("Name() = %q", chain.Name())
+	}
+}
+
+func TestFallbackProviderStopsOnBadRequest(t *testing.T) {
+	first := &failingProvider{name: "claude", err: genai.APIError{Code: 400, Message: "bad request"}}
+	second := &failingProvider{name: "gemini", text: "summary"}
+	chain := NewFallbackProvider(first, second)
+
+	if _, err := chain.GenerateTitle(context.Background(), "summary", "system"); err == nil || !strings.Contains(err.Error(), "claude:") {
+		t.Fatalf("GenerateTitle() error = %v", err)
+	}
+	if second.calls != 0 {
+		t.Fatalf("second provider called %d times", second.calls)
+	}
+}
+
+func TestFallbackProviderJoinsErrors(t *testing.T) {
+	chain := NewFallbackProvider(
+		&failingProvider{name: "claude", err: genai.APIError{Code: 503}},
+		&failingProvider{name: "openai", err: genai.APIError{Code: 500}},
+	)
+
+	_, err := chain.Chat(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "claude:") || !strings.Contains(err.Error(), "openai:") {
+		t.Fatalf("Chat() error = %v", err)
+	}
+}
+
+func TestFallbackProviderKeepsPartialStream(t *testing.T) {
+	first := &failingProvider{name: "claude", text: "partial", err: genai.APIError{Code: 500}}
+	second := &failingProvider{name: "gemini", text: "summary"}
+	chain := NewFallbackProvider(first, second)
+
+	var out strings.Builder
+	if _, err := chain.SummarizeStream(context.Background(), "notes", "system", &out); err == nil {
+		t.Fatal("SummarizeStream() error = nil")
+	}
+	if second.calls != 0 || out.String() != "partial" {
+		t.Fatalf("second calls = %d, output = %q", second.calls, out.String())
+	}
+}
+
+func TestShouldFailover(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{genai.APIError{Code: 401}, true},
+		{genai.APIError{Code: 429}, true},
+		{fmt.Errorf("wrapped: %w", genai.APIError{Code: 502}), true},
+		{genai.APIError{Code: 404}, false},
+		{context.Canceled, false},
+		{errors.New("empty response"), false},
+	}
+	for _, tt := range tests {
+		if got := ShouldFailover(tt.err); got != tt.want {
+			t.Errorf("ShouldFailover(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// failingProvider returns err from every call, after streaming text if set.
+type failingProvider struct {
+	name  string
+	text  string
+	err   error
+	calls int
+}
+
+func (p *failingProvider) Name() string { return p.name }
+
+func (p *failingProvider) Summarize(ctx context.Context, notes string, systemPrompt string) (string, error) {
+	return p.result()
+}
+
+func (p *failingProvider) SummarizeStream(ctx context.Context, notes string, systemPrompt string, w io.Writer) (string, error) {
+	if _, err := io.WriteString(w, p.text); err != nil {
+		return "", err
+	}
+	return p.result()
+}
+
+func (p *failingProvider) GenerateTitle(ctx context.Context, summary string, systemPrompt string) (string, error) {
+	return p.result()
+}
+
+func (p *failingProvider) Chat(ctx context.Context, messages []Message) (string, error) {
+	return p.result()
+}
+
+func (p *failingProvider) result() (string, error) {
+	p.calls++
+	if p.err != nil {
+		return "", p.err
+	}
+	return p.text, nil
+}
@@ -3,6 +3,7 @@ package ai
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"google.golang.org/genai"
@@ -36,6 +37,15 @@ func (p *GeminiProvider) Name() string {
 	return "gemini"
 }
 
+func (p *GeminiProvider) EstimateTokens(text string) int {
+	return estimateTokens(text, 1.2, 4)
+}
+
+// MaxInputTokens uses part of the 1M context window of Gemini models.
+func (p *GeminiProvider) MaxInputTokens() int {
+	return 500_000
+}
+
 func (p *GeminiProvider) Summarize(ctx context.Context, notes string, systemPrompt string) (string, error) {
 	return p.Chat(ctx, []Message{
 		{Role: "system", Content: systemPrompt},
@@ -43,9 +53,41 @@ func (p *GeminiProvider) Summarize(ctx context.Context, notes string, systemProm
 	})
 }
 
-func (p *GeminiProvider) GenerateTitle(ctx context.Context, summary string) (string, error) {
+func (p *GeminiProvider) SummarizeStream(ctx context.Context, notes string, systemPrompt string, w io.Writer) (string, error) {
+	contents, config := geminiRequest([]Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: notes},
+	})
+
+	var sb strings.Builder
+	for response, err := range p.client.Models.GenerateContentStream(ctx, p.model, contents, config) {
+		if err != nil {
+			return "", fmt.Errorf("gemini generate content failed: %w", err)
+		}
+		delta := response.Text()
+		sb.WriteString(delta)
+		if _, err := io.WriteString(w, delta); err != nil {
+			return "", err
+		}
+	}
+
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return "", fmt.Errorf("gemini returned empty content")
+	}
+	return text, nil
+}
+
+func (p *GeminiProvider) SummarizeWithImages(ctx context.Context, notes string, systemPrompt string, images []Image) (string, error) {
+	return p.Chat(ctx, []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: notes, Images: images},
+	})
+}
+
+func (p *GeminiProvider) GenerateTitle(ctx context.Context, summary string, systemPrompt string) (string, error) {
 	return p.Chat(ctx, []Message{
-		{Role: "system", Content: titleSystemPrompt},
+		{Role: "system", Content: systemPrompt},
 		{Role: "user", Content: summary},
 	})
 }
@@ -68,7 +110,11 @@ func geminiRequest(messages []Message) ([]*genai.Content, *genai.GenerateContent
 		case "assistant":
 			contents = append(contents, genai.NewContentFromText(content, genai.RoleModel))
 		default:
-			contents = append(contents, genai.NewContentFromText(content, genai.RoleUser))
+			parts := []*genai.Part{genai.NewPartFromText(content)}
+			for _, image := range message.Images {
+				parts = append(parts, genai.NewPartFromBytes(image.Data, image.MediaType))
+			}
+			contents = append(contents, genai.NewContentFromParts(parts, genai.RoleUser))
 		}
 	}
 
@@ -1,6 +1,11 @@
 package ai
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"google.golang.org/genai"
@@ -51,3 +56,52 @@ func assertGeminiContent(t *testing.T, got *genai.Content, wantRole genai.Role,
 		t.Fatalf("text = %q, want %q", got.Parts[0].Text, wantText)
 	}
 }
+
+func TestGeminiRequestWithImages(t *testing.T) {
+	contents, _ := geminiRequest([]Message{
+		{Role: "user", Content: "notes", Images: []Image{{MediaType: "image/webp", Data: []byte("webp")}}},
+	})
+
+	if len(contents) != 1 || len(contents[0].Parts) != 2 {
+		t.Fatalf("contents = %#v", contents)
+	}
+	if contents[0].Parts[0].Text != "notes" {
+		t.Fatalf("text = %q, want notes", contents[0].Parts[0].Text)
+	}
+	blob := contents[0].Parts[1].InlineData
+	if blob == nil || blob.MIMEType != "image/webp" || string(blob.Data) != "webp" {
+		t.Fatalf("inline data = %#v", blob)
+	}
+}
+
+func TestGeminiSummarizeStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, ":streamGenerateContent") {
+			t.Errorf("path = %q, want streamGenerateContent", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{"朝は", "散歩"} {
+			_, _ = fmt.Fprintf(w, "data: {\"candidates\":[{\"content\":{\"role\":\"model\",\"parts\":[{\"text\":%q}]}}]}\n\n", delta)
+		}
+	}))
+	defer server.Close()
+
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      "key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	provider := &GeminiProvider{client: client, model: "m"}
+
+	var streamed strings.Builder
+	got, err := provider.SummarizeStream(context.Background(), "notes", "system", &streamed)
+	if err != nil {
+		t.Fatalf("SummarizeStream() error = %v", err)
+	}
+	if got != "朝は散歩" || streamed.String() != "朝は散歩" {
+		t.Fatalf("SummarizeStream() = %q, streamed %q", got, streamed.String())
+	}
+}
@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	openai "github.com/openai/openai-go/v3"
+	openaioption "github.com/openai/openai-go/v3/option"
+)
+
+// LocalDefaultBaseURL is the OpenAI-compatible endpoint of a local Ollama.
+const LocalDefaultBaseURL = "http://localhost:11434/v1"
+
+// LocalProvider talks to Ollama or any other OpenAI-compatible server. It
+// sends no API key, so notes never need to leave the machine.
+type LocalProvider struct {
+	client openai.Client
+	model  string
+}
+
+func NewLocalProvider(baseURL, model string) *LocalProvider {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = LocalDefaultBaseURL
+	}
+	return &LocalProvider{
+		client: openai.NewClient(
+			openaioption.WithBaseURL(baseURL),
+			openaioption.WithAPIKey(""),
+			openaioption.WithHeaderDel("Authorization"),
+		),
+		model: model,
+	}
+}
+
+func (p *LocalProvider) Name() string {
+	return "local"
+}
+
+func (p *LocalProvider) EstimateTokens(text string) int {
+	return estimateTokens(text, 1, 3.5)
+}
+
+// MaxInputTokens assumes the small context windows local models run with by
+// default; raise ai.max_input_tokens for models configured with more.
+func (p *LocalProvider) MaxInputTokens() int {
+	return 8_000
+}
+
+func (p *LocalProvider) Summarize(ctx context.Context, notes string, systemPrompt string) (string, error) {
+	return p.Chat(ctx, []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: notes},
+	})
+}
+
+func (p *LocalProvider) SummarizeStream(ctx context.Context, notes string, systemPrompt string, w io.Writer) (string, error) {
+	return chatCompletionStream(ctx, p.client, p.model, p.Name(), localMessages([]Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: notes},
+	}), w)
+}
+
+func (p *LocalProvider) SummarizeWithImages(ctx context.Context, notes string, systemPrompt string, images []Image) (string, error) {
+	return p.Chat(ctx, []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: notes, Images: images},
+	})
+}
+
+func (p *LocalProvider) GenerateTitle(ctx context.Context, summary string, systemPrompt string) (string, error) {
+	return p.Chat(ctx, []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: summary},
+	})
+}
+
+func (p *LocalProvider) Chat(ctx context.Context, messages []Message) (string, error) {
+	return chatCompletion(ctx, p.client, p.model, p.Name(), localMessages(messages))
+}
+
+// localMessages uses the "system" role, which OpenAI-compatible servers
+// support more widely than "developer".
+func localMessages(messages []Message) []openai.ChatCompletionMessageParamUnion {
+	return chatCompletionMessages(messages, openai.SystemMessage[string])
+}
@@ -0,0 +1,50 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocalProviderChat(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-should-not-be-sent")
+
+	var gotAuth string
+	var gotBody struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		gotAuth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"c","object":"chat.completion","created":1,"model":"llama3","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":" 朝は散歩 "}}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewLocalProvider(server.URL+"/v1", "llama3")
+	got, err := provider.Summarize(context.Background(), "notes", "system")
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+
+	if got != "朝は散歩" {
+		t.Fatalf("Summarize() = %q", got)
+	}
+	if gotAuth != "" {
+		t.Fatalf("Authorization = %q, want none", gotAuth)
+	}
+	if gotBody.Model != "llama3" || len(gotBody.Messages) != 2 || gotBody.Messages[0].Role != "system" || gotBody.Messages[1].Content != "notes" {
+		t.Fatalf("request = %#v", gotBody)
+	}
+}
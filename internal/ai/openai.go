@@ -2,7 +2,9 @@ package ai
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
 	"strings"
 
 	openai "github.com/openai/openai-go/v3"
@@ -30,6 +32,16 @@ func (p *OpenAIProvider) Name() string {
 	return "openai"
 }
 
+func (p *OpenAIProvider) EstimateTokens(text string) int {
+	return estimateTokens(text, 1.2, 4)
+}
+
+// MaxInputTokens stays well below the context window of current GPT models
+// so long prompts do not degrade the summary.
+func (p *OpenAIProvider) MaxInputTokens() int {
+	return 100_000
+}
+
 func (p *OpenAIProvider) Summarize(ctx context.Context, notes string, systemPrompt string) (string, error) {
 	return p.Chat(ctx, []Message{
 		{Role: "system", Content: systemPrompt},
@@ -37,33 +49,89 @@ func (p *OpenAIProvider) Summarize(ctx context.Context, notes string, systemProm
 	})
 }
 
-func (p *OpenAIProvider) GenerateTitle(ctx context.Context, summary string) (string, error) {
+func (p *OpenAIProvider) SummarizeStream(ctx context.Context, notes string, systemPrompt string, w io.Writer) (string, error) {
+	return chatCompletionStream(ctx, p.client, p.model, p.Name(), openAIMessages([]Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: notes},
+	}), w)
+}
+
+func (p *OpenAIProvider) SummarizeWithImages(ctx context.Context, notes string, systemPrompt string, images []Image) (string, error) {
+	return p.Chat(ctx, []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: notes, Images: images},
+	})
+}
+
+func (p *OpenAIProvider) GenerateTitle(ctx context.Context, summary string, systemPrompt string) (string, error) {
 	return p.Chat(ctx, []Message{
-		{Role: "system", Content: titleSystemPrompt},
+		{Role: "system", Content: systemPrompt},
 		{Role: "user", Content: summary},
 	})
 }
 
 func (p *OpenAIProvider) Chat(ctx context.Context, messages []Message) (string, error) {
-	response, err := p.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-		Model:    openai.ChatModel(p.model),
-		Messages: openAIMessages(messages),
+	return chatCompletion(ctx, p.client, p.model, p.Name(), openAIMessages(messages))
+}
+
+// chatCompletion sends messages to an OpenAI-compatible chat completions API.
+// name prefixes error messages.
+func chatCompletion(ctx context.Context, client openai.Client, model, name string, messages []openai.ChatCompletionMessageParamUnion) (string, error) {
+	response, err := client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+		Model:    openai.ChatModel(model),
+		Messages: messages,
 	})
 	if err != nil {
-		return "", fmt.Errorf("openai chat completions API failed: %w", err)
+		return "", fmt.Errorf("%s chat completions API failed: %w", name, err)
 	}
 
 	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("openai returned no choices")
+		return "", fmt.Errorf("%s returned no choices", name)
 	}
 	text := strings.TrimSpace(response.Choices[0].Message.Content)
 	if text == "" {
-		return "", fmt.Errorf("openai returned empty content")
+		return "", fmt.Errorf("%s returned empty content", name)
+	}
+	return text, nil
+}
+
+// chatCompletionStream is chatCompletion that writes the response to w as it arrives.
+func chatCompletionStream(ctx context.Context, client openai.Client, model, name string, messages []openai.ChatCompletionMessageParamUnion, w io.Writer) (string, error) {
+	stream := client.Chat.Completions.NewStreaming(ctx, openai.ChatCompletionNewParams{
+		Model:    openai.ChatModel(model),
+		Messages: messages,
+	})
+	defer func() { _ = stream.Close() }()
+
+	var sb strings.Builder
+	for stream.Next() {
+		chunk := stream.Current()
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := chunk.Choices[0].Delta.Content
+		sb.WriteString(delta)
+		if _, err := io.WriteString(w, delta); err != nil {
+			return "", err
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return "", fmt.Errorf("%s chat completions API failed: %w", name, err)
+	}
+
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return "", fmt.Errorf("%s returned empty content", name)
 	}
 	return text, nil
 }
 
 func openAIMessages(messages []Message) []openai.ChatCompletionMessageParamUnion {
+	return chatCompletionMessages(messages, openai.DeveloperMessage[string])
+}
+
+// chatCompletionMessages converts messages, sending system prompts with systemMessage.
+func chatCompletionMessages(messages []Message, systemMessage func(string) openai.ChatCompletionMessageParamUnion) []openai.ChatCompletionMessageParamUnion {
 	out := make([]openai.ChatCompletionMessageParamUnion, 0, len(messages))
 	for _, message := range messages {
 		content := strings.TrimSpace(message.Content)
@@ -73,11 +141,21 @@ func openAIMessages(messages []Message) []openai.ChatCompletionMessageParamUnion
 
 		switch strings.ToLower(strings.TrimSpace(message.Role)) {
 		case "system", "developer":
-			out = append(out, openai.DeveloperMessage(content))
+			out = append(out, systemMessage(content))
 		case "assistant":
 			out = append(out, openai.AssistantMessage(content))
 		default:
-			out = append(out, openai.UserMessage(content))
+			if len(message.Images) == 0 {
+				out = append(out, openai.UserMessage(content))
+				continue
+			}
+			parts := []openai.ChatCompletionContentPartUnionParam{openai.TextContentPart(content)}
+			for _, image := range message.Images {
+				parts = append(parts, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{
+					URL: "data:" + image.MediaType + ";base64," + base64.StdEncoding.EncodeToString(image.Data),
+				}))
+			}
+			out = append(out, openai.UserMessage(parts))
 		}
 	}
 	return out
@@ -1,6 +1,16 @@
 package ai
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	openai "github.com/openai/openai-go/v3"
+	openaioption "github.com/openai/openai-go/v3/option"
+)
 
 func TestOpenAIMessages(t *testing.T) {
 	messages := []Message{
@@ -34,3 +44,45 @@ func TestOpenAIMessages(t *testing.T) {
 		t.Fatalf("message[4] = %#v", got[4])
 	}
 }
+
+func TestOpenAIMessagesWithImages(t *testing.T) {
+	got := openAIMessages([]Message{
+		{Role: "user", Content: "notes", Images: []Image{{MediaType: "image/png", Data: []byte("png")}}},
+	})
+
+	if len(got) != 1 || got[0].OfUser == nil {
+		t.Fatalf("messages = %#v", got)
+	}
+	parts := got[0].OfUser.Content.OfArrayOfContentParts
+	if len(parts) != 2 || parts[0].OfText == nil || parts[0].OfText.Text != "notes" {
+		t.Fatalf("parts = %#v", parts)
+	}
+	if parts[1].OfImageURL == nil || parts[1].OfImageURL.ImageURL.URL != "data:image/png;base64,cG5n" {
+		t.Fatalf("image part = %#v", parts[1])
+	}
+}
+
+func TestOpenAISummarizeStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{"朝は", "散歩"} {
+			_, _ = fmt.Fprintf(w, "data: {\"id\":\"c\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", delta)
+		}
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider := &OpenAIProvider{
+		client: openai.NewClient(openaioption.WithAPIKey("key"), openaioption.WithBaseURL(server.URL)),
+		model:  "m",
+	}
+
+	var streamed strings.Builder
+	got, err := provider.SummarizeStream(context.Background(), "notes", "system", &streamed)
+	if err != nil {
+		t.Fatalf("SummarizeStream() error = %v", err)
+	}
+	if got != "朝は散歩" || streamed.String() != "朝は散歩" {
+		t.Fatalf("SummarizeStream() = %q, streamed %q", got, streamed.String())
+	}
+}
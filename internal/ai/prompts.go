@@ -0,0 +1,140 @@
+package ai
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/soli0222/diary-cli/internal/i18n"
+)
+
+// Prompt template names. Each has a built-in default and can be overridden
+// by a file with the same name in the prompts directory.
+const (
+	PromptSummarySystem = "summary_system.tmpl"
+	PromptSummary       = "summary.tmpl"
+	PromptTitleSystem   = "title_system.tmpl"
+	PromptTitle         = "title.tmpl"
+	PromptDigestSystem  = "digest_system.tmpl"
+	PromptDigest        = "digest.tmpl"
+	PromptMergeSystem   = "merge_system.tmpl"
+	PromptMerge         = "merge.tmpl"
+)
+
+var promptNames = []string{
+	PromptSummarySystem,
+	PromptSummary,
+	PromptTitleSystem,
+	PromptTitle,
+	PromptDigestSystem,
+	PromptDigest,
+	PromptMergeSystem,
+	PromptMerge,
+}
+
+//go:embed prompts/*/*.tmpl
+var defaultPromptFS embed.FS
+
+// PromptTimeGroup describes one time-of-day group passed to prompt templates.
+type PromptTimeGroup struct {
+	Label     string
+	NoteCount int
+}
+
+// PromptData holds the variables available to prompt templates.
+type PromptData struct {
+	Date       string
+	Author     string
+	NoteCount  int
+	TimeGroups []PromptTimeGroup
+	Notes      string
+	Summary    string
+	Period     string
+	Diaries    string
+	// Partials holds the per-section summaries merged on long days.
+	Partials string
+
+	// CiteSources is set when notes are tagged with "[note:ID]" for citation.
+	CiteSources bool
+}
+
+// Prompts is a set of parsed prompt templates.
+type Prompts struct {
+	templates map[string]*template.Template
+}
+
+// DefaultPrompts returns the built-in Japanese prompt templates.
+func DefaultPrompts() *Prompts {
+	prompts, err := LoadPrompts("", i18n.DefaultLanguage)
+	if err != nil {
+		panic(err)
+	}
+	return prompts
+}
+
+// LoadPrompts parses the built-in templates for language, replacing any that
+// have an override file in dir. An empty or missing dir yields the defaults.
+func LoadPrompts(dir, language string) (*Prompts, error) {
+	if language == "" {
+		language = i18n.DefaultLanguage
+	}
+	if _, err := fs.Stat(defaultPromptFS, path.Join("prompts", language)); err != nil {
+		return nil, fmt.Errorf("no built-in prompts for language %q", language)
+	}
+
+	prompts := &Prompts{templates: make(map[string]*template.Template, len(promptNames))}
+
+	for _, name := range promptNames {
+		source, err := readPromptSource(dir, language, name)
+		if err != nil {
+			return nil, err
+		}
+
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(strings.TrimSuffix(source, "\n"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse prompt template %s: %w", name, err)
+		}
+		prompts.templates[name] = tmpl
+	}
+
+	return prompts, nil
+}
+
+// Render executes the named template with data.
+func (p *Prompts) Render(name string, data PromptData) (string, error) {
+	tmpl, ok := p.templates[name]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt template: %s", name)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+func readPromptSource(dir, language, name string) (string, error) {
+	if dir != "" {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return string(content), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to read prompt template %s: %w", name, err)
+		}
+	}
+
+	content, err := defaultPromptFS.ReadFile(path.Join("prompts", language, name))
+	if err != nil {
+		return "", fmt.Errorf("failed to read default prompt template %s: %w", name, err)
+	}
+	return string(content), nil
+}
@@ -1,15 +1,44 @@
 package ai
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string  `json:"role"`
+	Content string  `json:"content"`
+	Images  []Image `json:"-"`
+}
+
+// Image is an inline image sent with a user message.
+type Image struct {
+	MediaType string
+	Data      []byte
+}
+
+// SupportedImageType reports whether every provider accepts images of mediaType.
+func SupportedImageType(mediaType string) bool {
+	switch mediaType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	default:
+		return false
+	}
 }
 
 type AIProvider interface {
 	Name() string
 	Summarize(ctx context.Context, notes string, systemPrompt string) (string, error)
-	GenerateTitle(ctx context.Context, summary string) (string, error)
+	// SummarizeStream is Summarize that writes text to w as it is generated
+	// and returns the complete response.
+	SummarizeStream(ctx context.Context, notes string, systemPrompt string, w io.Writer) (string, error)
+	GenerateTitle(ctx context.Context, summary string, systemPrompt string) (string, error)
 	Chat(ctx context.Context, messages []Message) (string, error)
 }
+
+// MultimodalProvider is implemented by providers that can read images
+// alongside the summary prompt.
+type MultimodalProvider interface {
+	SummarizeWithImages(ctx context.Context, notes string, systemPrompt string, images []Image) (string, error)
+}
@@ -3,56 +3,236 @@ package ai
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
-	"time"
 )
 
-const SummarySystemPrompt = `あなたはMisskeyノートをもとに、その日の出来事を時系列で整理するアシスタントです。
+func GenerateSummary(ctx context.Context, provider AIProvider, prompts *Prompts, data PromptData) (string, error) {
+	return GenerateSummaryWithImages(ctx, provider, prompts, data, nil)
+}
 
-ルール:
-- 日本語で書く
-- 時間帯ごとに見出しをつけて整理する
-- 事実ベースで簡潔にまとめる
-- ユーザーの気分や関心の流れが分かるようにする
-- 絵文字は使わない
-- Markdown本文のみを返す`
+// GenerateSummaryWithImages is GenerateSummary with images attached for
+// providers that implement MultimodalProvider. Other providers get text only.
+func GenerateSummaryWithImages(ctx context.Context, provider AIProvider, prompts *Prompts, data PromptData, images []Image) (string, error) {
+	return GenerateSummaryStream(ctx, provider, prompts, data, images, nil)
+}
 
-const titleSystemPrompt = `あなたは日記タイトルを付けるアシスタントです。
+// GenerateSummaryStream is GenerateSummaryWithImages that writes the summary
+// to w as it is generated. Multimodal requests are not streamed; their
+// response is written to w once complete.
+func GenerateSummaryStream(ctx context.Context, provider AIProvider, prompts *Prompts, data PromptData, images []Image, w io.Writer) (string, error) {
+	system, prompt, err := renderPromptPair(prompts, PromptSummarySystem, PromptSummary, data)
+	if err != nil {
+		return "", err
+	}
+	text, err := summarize(ctx, provider, system, prompt, images, w)
+	if err != nil {
+		return "", fmt.Errorf("%s summary failed: %w", provider.Name(), err)
+	}
+	return text, nil
+}
 
-ルール:
-- 日本語
-- 10〜30文字程度
-- その日の象徴的な話題や感触が伝わる短いタイトル
-- タイトル本文のみを返す`
+// summarize sends one summary request, with images when the provider takes
+// them, and streams it to w when w is non-nil.
+func summarize(ctx context.Context, provider AIProvider, system, prompt string, images []Image, w io.Writer) (string, error) {
+	if mp, ok := provider.(MultimodalProvider); ok && len(images) > 0 {
+		text, err := mp.SummarizeWithImages(ctx, prompt, system, images)
+		if err != nil {
+			return "", err
+		}
+		text = strings.TrimSpace(text)
+		if w != nil {
+			if _, err := io.WriteString(w, text); err != nil {
+				return "", err
+			}
+		}
+		return text, nil
+	}
 
-func BuildSummaryPrompt(date time.Time, formattedNotes string) string {
-	return fmt.Sprintf(
-		"対象日: %s\n\n以下はMisskeyノートを時間帯ごとに整理したものです。1日のサマリーを作成してください。\n\n%s",
-		date.Format("2006-01-02"),
-		formattedNotes,
-	)
+	var text string
+	var err error
+	if w != nil {
+		text, err = provider.SummarizeStream(ctx, prompt, system, w)
+	} else {
+		text, err = provider.Summarize(ctx, prompt, system)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(text), nil
 }
 
-func BuildTitlePrompt(date time.Time, summary string) string {
-	return fmt.Sprintf(
-		"対象日: %s\n\n以下のサマリーにタイトルを付けてください。\n\n%s",
-		date.Format("2006-01-02"),
-		summary,
-	)
+// PromptChunk is one part of a long day's notes, summarized on its own.
+type PromptChunk struct {
+	Label     string
+	NoteCount int
+	Notes     string
 }
 
-func GenerateSummary(ctx context.Context, provider AIProvider, formattedNotes string, date time.Time) (string, error) {
-	text, err := provider.Summarize(ctx, BuildSummaryPrompt(date, formattedNotes), SummarySystemPrompt)
+// SummaryPromptTokens estimates the size of the summary prompt for data.
+func SummaryPromptTokens(provider AIProvider, prompts *Prompts, data PromptData) (int, error) {
+	system, prompt, err := renderPromptPair(prompts, PromptSummarySystem, PromptSummary, data)
 	if err != nil {
-		return "", fmt.Errorf("%s summary failed: %w", provider.Name(), err)
+		return 0, err
 	}
-	return strings.TrimSpace(text), nil
+	return EstimateTokens(provider, system+prompt), nil
+}
+
+// GenerateChunkedSummary summarizes each chunk separately and merges the
+// partial summaries in a final call. While the merge prompt exceeds budget,
+// neighbouring partials are first merged in batches that fit. Images go with
+// the final merge call only. When w is non-nil, the merged summary is written
+// to it as it is generated.
+func GenerateChunkedSummary(ctx context.Context, provider AIProvider, prompts *Prompts, data PromptData, chunks []PromptChunk, budget int, images []Image, w io.Writer) (string, error) {
+	parts := make([]partialSummary, 0, len(chunks))
+	for _, chunk := range chunks {
+		chunkData := data
+		chunkData.Notes = chunk.Notes
+		chunkData.NoteCount = chunk.NoteCount
+		chunkData.TimeGroups = []PromptTimeGroup{{Label: chunk.Label, NoteCount: chunk.NoteCount}}
+
+		text, err := GenerateSummary(ctx, provider, prompts, chunkData)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, partialSummary{label: chunk.Label, text: text})
+	}
+
+	for len(parts) > 1 {
+		tokens, err := mergePromptTokens(provider, prompts, data, parts)
+		if err != nil {
+			return "", err
+		}
+		if tokens <= budget {
+			break
+		}
+		merged, err := mergeInBatches(ctx, provider, prompts, data, parts, budget)
+		if err != nil {
+			return "", err
+		}
+		if len(merged) == len(parts) {
+			// No two partials fit together; merging further cannot help.
+			break
+		}
+		parts = merged
+	}
+
+	data.Partials = formatPartials(parts)
+	system, prompt, err := renderPromptPair(prompts, PromptMergeSystem, PromptMerge, data)
+	if err != nil {
+		return "", err
+	}
+	text, err := summarize(ctx, provider, system, prompt, images, w)
+	if err != nil {
+		return "", fmt.Errorf("%s summary merge failed: %w", provider.Name(), err)
+	}
+	return text, nil
+}
+
+// partialSummary is the summary of one chunk, or of neighbouring chunks
+// merged together.
+type partialSummary struct {
+	label string
+	text  string
+}
+
+func formatPartials(parts []partialSummary) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		fmt.Fprintf(&sb, "## %s\n%s\n\n", p.label, p.text)
+	}
+	return strings.TrimSpace(sb.String())
 }
 
-func GenerateTitle(ctx context.Context, provider AIProvider, summary string, date time.Time) (string, error) {
-	text, err := provider.GenerateTitle(ctx, BuildTitlePrompt(date, summary))
+func mergePromptTokens(provider AIProvider, prompts *Prompts, data PromptData, parts []partialSummary) (int, error) {
+	data.Partials = formatPartials(parts)
+	system, prompt, err := renderPromptPair(prompts, PromptMergeSystem, PromptMerge, data)
+	if err != nil {
+		return 0, err
+	}
+	return EstimateTokens(provider, system+prompt), nil
+}
+
+// mergeInBatches merges runs of neighbouring partials whose merge prompt fits
+// budget into one partial each. A partial that fits with no neighbour is kept
+// as it is.
+func mergeInBatches(ctx context.Context, provider AIProvider, prompts *Prompts, data PromptData, parts []partialSummary, budget int) ([]partialSummary, error) {
+	var merged []partialSummary
+	for start := 0; start < len(parts); {
+		end := start + 1
+		for end < len(parts) {
+			tokens, err := mergePromptTokens(provider, prompts, data, parts[start:end+1])
+			if err != nil {
+				return nil, err
+			}
+			if tokens > budget {
+				break
+			}
+			end++
+		}
+		if end-start == 1 {
+			merged = append(merged, parts[start])
+			start = end
+			continue
+		}
+
+		batchData := data
+		batchData.Partials = formatPartials(parts[start:end])
+		system, prompt, err := renderPromptPair(prompts, PromptMergeSystem, PromptMerge, batchData)
+		if err != nil {
+			return nil, err
+		}
+		text, err := provider.Summarize(ctx, prompt, system)
+		if err != nil {
+			return nil, fmt.Errorf("%s summary merge failed: %w", provider.Name(), err)
+		}
+		merged = append(merged, partialSummary{
+			label: parts[start].label + " - " + parts[end-1].label,
+			text:  strings.TrimSpace(text),
+		})
+		start = end
+	}
+	return merged, nil
+}
+
+func GenerateTitle(ctx context.Context, provider AIProvider, prompts *Prompts, data PromptData) (string, error) {
+	system, prompt, err := renderPromptPair(prompts, PromptTitleSystem, PromptTitle, data)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := provider.GenerateTitle(ctx, prompt, system)
 	if err != nil {
 		return "", fmt.Errorf("%s title generation failed: %w", provider.Name(), err)
 	}
 	return strings.TrimSpace(text), nil
 }
+
+func GenerateDigest(ctx context.Context, provider AIProvider, prompts *Prompts, data PromptData) (string, error) {
+	system, prompt, err := renderPromptPair(prompts, PromptDigestSystem, PromptDigest, data)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := provider.Summarize(ctx, prompt, system)
+	if err != nil {
+		return "", fmt.Errorf("%s digest failed: %w", provider.Name(), err)
+	}
+	return strings.TrimSpace(text), nil
+}
+
+func renderPromptPair(prompts *Prompts, systemName, userName string, data PromptData) (string, string, error) {
+	if prompts == nil {
+		prompts = DefaultPrompts()
+	}
+
+	system, err := prompts.Render(systemName, data)
+	if err != nil {
+		return "", "", err
+	}
+	prompt, err := prompts.Render(userName, data)
+	if err != nil {
+		return "", "", err
+	}
+	return system, prompt, nil
+}
@@ -1,30 +1,313 @@
 package ai
 
 import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
-	"time"
 )
 
-func TestBuildSummaryPrompt(t *testing.T) {
-	date := time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC)
-	notes := "05時台\n- 朝のノート"
-
-	got := BuildSummaryPrompt(date, notes)
+func TestDefaultSummaryPrompt(t *testing.T) {
+	got, err := DefaultPrompts().Render(PromptSummary, PromptData{
+		Date:  "2026-02-23",
+		Notes: "05時台\n- 朝のノート",
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
 	want := "対象日: 2026-02-23\n\n以下はMisskeyノートを時間帯ごとに整理したものです。1日のサマリーを作成してください。\n\n05時台\n- 朝のノート"
 
 	if got != want {
-		t.Fatalf("BuildSummaryPrompt() = %q, want %q", got, want)
+		t.Fatalf("summary prompt = %q, want %q", got, want)
 	}
 }
 
-func TestBuildTitlePrompt(t *testing.T) {
-	date := time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC)
-	summary := "朝から開発を進めた。"
-
-	got := BuildTitlePrompt(date, summary)
+func TestDefaultTitlePrompt(t *testing.T) {
+	got, err := DefaultPrompts().Render(PromptTitle, PromptData{
+		Date:    "2026-02-23",
+		Summary: "朝から開発を進めた。",
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
 	want := "対象日: 2026-02-23\n\n以下のサマリーにタイトルを付けてください。\n\n朝から開発を進めた。"
 
 	if got != want {
-		t.Fatalf("BuildTitlePrompt() = %q, want %q", got, want)
+		t.Fatalf("title prompt = %q, want %q", got, want)
+	}
+}
+
+func TestDefaultDigestPrompt(t *testing.T) {
+	got, err := DefaultPrompts().Render(PromptDigest, PromptData{
+		Period:  "2026-W41",
+		Diaries: "### 2026-10-05\n散歩した。",
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := "対象期間: 2026-W41\n\n以下はこの期間の日ごとの日記です。期間全体の振り返りを作成してください。\n\n### 2026-10-05\n散歩した。"
+
+	if got != want {
+		t.Fatalf("digest prompt = %q, want %q", got, want)
+	}
+}
+
+func TestLoadPromptsOverride(t *testing.T) {
+	dir := t.TempDir()
+	override := "Write an English diary for {{.Author}} on {{.Date}} ({{.NoteCount}} notes).{{range .TimeGroups}}\n- {{.Label}}: {{.NoteCount}}{{end}}\n"
+	if err := os.WriteFile(filepath.Join(dir, PromptSummarySystem), []byte(override), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
 	}
+
+	prompts, err := LoadPrompts(dir, "ja")
+	if err != nil {
+		t.Fatalf("LoadPrompts() error = %v", err)
+	}
+
+	provider := &recordingProvider{}
+	_, err = GenerateSummary(context.Background(), provider, prompts, PromptData{
+		Date:       "2026-02-23",
+		Author:     "soli",
+		NoteCount:  3,
+		TimeGroups: []PromptTimeGroup{{Label: "morning", NoteCount: 2}, {Label: "night", NoteCount: 1}},
+		Notes:      "notes",
+	})
+	if err != nil {
+		t.Fatalf("GenerateSummary() error = %v", err)
+	}
+
+	want := "Write an English diary for soli on 2026-02-23 (3 notes).\n- morning: 2\n- night: 1"
+	if provider.system != want {
+		t.Fatalf("system prompt = %q, want %q", provider.system, want)
+	}
+	if !strings.HasPrefix(provider.prompt, "対象日: 2026-02-23") {
+		t.Fatalf("user prompt should fall back to default, got %q", provider.prompt)
+	}
+}
+
+func TestLoadPromptsRejectsInvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, PromptTitle), []byte("{{.Date"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := LoadPrompts(dir, "ja"); err == nil {
+		t.Fatal("LoadPrompts() error = nil, want parse error")
+	}
+}
+
+func TestLoadPromptsEnglish(t *testing.T) {
+	prompts, err := LoadPrompts("", "en")
+	if err != nil {
+		t.Fatalf("LoadPrompts() error = %v", err)
+	}
+
+	got, err := prompts.Render(PromptTitle, PromptData{Date: "2026-02-23", Summary: "Worked on code."})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got != "Date: 2026-02-23\n\nPlease give the following summary a title.\n\nWorked on code." {
+		t.Fatalf("title prompt = %q", got)
+	}
+
+	if _, err := LoadPrompts("", "fr"); err == nil {
+		t.Fatal("LoadPrompts(fr) error = nil, want unsupported language")
+	}
+}
+
+func TestGenerateSummaryWithImages(t *testing.T) {
+	images := []Image{{MediaType: "image/png", Data: []byte("png")}}
+
+	multimodal := &multimodalProvider{}
+	if _, err := GenerateSummaryWithImages(context.Background(), multimodal, nil, PromptData{Date: "2026-02-23"}, images); err != nil {
+		t.Fatalf("GenerateSummaryWithImages() error = %v", err)
+	}
+	if len(multimodal.images) != 1 {
+		t.Fatalf("images = %#v, want the attached image", multimodal.images)
+	}
+
+	textOnly := &recordingProvider{}
+	if _, err := GenerateSummaryWithImages(context.Background(), textOnly, nil, PromptData{Date: "2026-02-23"}, images); err != nil {
+		t.Fatalf("GenerateSummaryWithImages() error = %v", err)
+	}
+	if !strings.HasPrefix(textOnly.prompt, "対象日: 2026-02-23") {
+		t.Fatalf("text-only provider should still get the prompt, got %q", textOnly.prompt)
+	}
+}
+
+func TestGenerateSummaryStream(t *testing.T) {
+	var streamed strings.Builder
+	provider := &recordingProvider{}
+
+	got, err := GenerateSummaryStream(context.Background(), provider, nil, PromptData{Date: "2026-02-23"}, nil, &streamed)
+	if err != nil {
+		t.Fatalf("GenerateSummaryStream() error = %v", err)
+	}
+	if got != "summary" || streamed.String() != "summary" {
+		t.Fatalf("summary = %q, streamed = %q", got, streamed.String())
+	}
+	if !strings.HasPrefix(provider.prompt, "対象日: 2026-02-23") {
+		t.Fatalf("prompt = %q", provider.prompt)
+	}
+
+	streamed.Reset()
+	multimodal := &multimodalProvider{}
+	images := []Image{{MediaType: "image/png", Data: []byte("png")}}
+	if _, err := GenerateSummaryStream(context.Background(), multimodal, nil, PromptData{Date: "2026-02-23"}, images, &streamed); err != nil {
+		t.Fatalf("GenerateSummaryStream() error = %v", err)
+	}
+	if len(multimodal.images) != 1 || streamed.String() != "summary" {
+		t.Fatalf("images = %#v, streamed = %q", multimodal.images, streamed.String())
+	}
+}
+
+func TestGenerateChunkedSummary(t *testing.T) {
+	provider := &recordingProvider{}
+	chunks := []PromptChunk{
+		{Label: "朝", NoteCount: 2, Notes: "- [07:00] 起床"},
+		{Label: "夜 (1/2)", NoteCount: 1, Notes: "- [22:00] 就寝"},
+	}
+
+	got, err := GenerateChunkedSummary(context.Background(), provider, nil, PromptData{Date: "2026-02-23"}, chunks, defaultMaxInputTokens, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateChunkedSummary() error = %v", err)
+	}
+	if got != "summary" {
+		t.Fatalf("summary = %q", got)
+	}
+	if !strings.Contains(provider.prompt, "## 朝\nsummary\n\n## 夜 (1/2)\nsummary") {
+		t.Fatalf("merge prompt = %q, want the partial summaries", provider.prompt)
+	}
+	if !strings.Contains(provider.system, "まとめます") {
+		t.Fatalf("merge system prompt = %q", provider.system)
+	}
+}
+
+func TestGenerateChunkedSummaryMergesInBatches(t *testing.T) {
+	provider := &promptLog{reply: strings.Repeat("あ", 200)}
+	chunks := []PromptChunk{
+		{Label: "朝", NoteCount: 1, Notes: "- [07:00] 起床"},
+		{Label: "昼", NoteCount: 1, Notes: "- [12:00] 昼食"},
+		{Label: "夕方", NoteCount: 1, Notes: "- [18:00] 帰宅"},
+		{Label: "夜", NoteCount: 1, Notes: "- [22:00] 就寝"},
+	}
+	data := PromptData{Date: "2026-02-23"}
+	// Room for two partials and longer labels, but not for three partials.
+	budget, err := mergePromptTokens(provider, nil, data, []partialSummary{
+		{label: "朝 - 昼", text: provider.reply},
+		{label: "夕方 - 夜", text: provider.reply},
+	})
+	if err != nil {
+		t.Fatalf("mergePromptTokens() error = %v", err)
+	}
+
+	if _, err := GenerateChunkedSummary(context.Background(), provider, nil, data, chunks, budget, nil, nil); err != nil {
+		t.Fatalf("GenerateChunkedSummary() error = %v", err)
+	}
+
+	if len(provider.prompts) != 7 {
+		t.Fatalf("calls = %d, want 4 chunks, 2 batch merges and the final merge", len(provider.prompts))
+	}
+	for i, prompt := range provider.prompts {
+		if tokens := EstimateTokens(provider, prompt); i >= 4 && tokens > budget {
+			t.Errorf("merge prompt %d = %d tokens, budget %d", i, tokens, budget)
+		}
+	}
+	final := provider.prompts[6]
+	if !strings.Contains(final, "## 朝 - 昼\n") || !strings.Contains(final, "## 夕方 - 夜\n") {
+		t.Fatalf("final merge prompt = %q, want the batch summaries", final)
+	}
+}
+
+func TestGenerateChunkedSummarySendsImagesWithMerge(t *testing.T) {
+	provider := &multimodalProvider{}
+	chunks := []PromptChunk{{Label: "朝", NoteCount: 1, Notes: "- [07:00] 起床"}}
+	images := []Image{{MediaType: "image/png", Data: []byte("png")}}
+
+	var streamed strings.Builder
+	got, err := GenerateChunkedSummary(context.Background(), provider, nil, PromptData{Date: "2026-02-23"}, chunks, defaultMaxInputTokens, images, &streamed)
+	if err != nil {
+		t.Fatalf("GenerateChunkedSummary() error = %v", err)
+	}
+	if len(provider.images) != 1 || !strings.Contains(provider.prompt, "## 朝") {
+		t.Fatalf("images = %#v, prompt = %q, want images with the merge prompt", provider.images, provider.prompt)
+	}
+	if got != "summary" || streamed.String() != "summary" {
+		t.Fatalf("summary = %q, streamed = %q", got, streamed.String())
+	}
+}
+
+func TestSummaryPromptTokens(t *testing.T) {
+	short, err := SummaryPromptTokens(&recordingProvider{}, nil, PromptData{Notes: "- [07:00] 起床"})
+	if err != nil {
+		t.Fatalf("SummaryPromptTokens() error = %v", err)
+	}
+	long, err := SummaryPromptTokens(&recordingProvider{}, nil, PromptData{Notes: strings.Repeat("- [07:00] 散歩した\n", 1000)})
+	if err != nil {
+		t.Fatalf("SummaryPromptTokens() error = %v", err)
+	}
+	if long-short < 5000 {
+		t.Fatalf("tokens: short = %d, long = %d", short, long)
+	}
+	if got := MaxInputTokens(&recordingProvider{}, 0); got != defaultMaxInputTokens {
+		t.Fatalf("MaxInputTokens() = %d, want default", got)
+	}
+	if got := MaxInputTokens(&ClaudeProvider{}, 1234); got != 1234 {
+		t.Fatalf("MaxInputTokens() override = %d", got)
+	}
+}
+
+// promptLog records every summary prompt and answers with reply.
+type promptLog struct {
+	recordingProvider
+	reply   string
+	prompts []string
+}
+
+func (p *promptLog) Summarize(ctx context.Context, notes string, systemPrompt string) (string, error) {
+	p.prompts = append(p.prompts, systemPrompt+notes)
+	return p.reply, nil
+}
+
+type multimodalProvider struct {
+	recordingProvider
+	images []Image
+}
+
+func (p *multimodalProvider) SummarizeWithImages(ctx context.Context, notes string, systemPrompt string, images []Image) (string, error) {
+	p.prompt, p.system, p.images = notes, systemPrompt, images
+	return "summary", nil
+}
+
+type recordingProvider struct {
+	system string
+	prompt string
+}
+
+func (p *recordingProvider) Name() string { return "recording" }
+
+func (p *recordingProvider) Summarize(ctx context.Context, notes string, systemPrompt string) (string, error) {
+	p.prompt, p.system = notes, systemPrompt
+	return "summary", nil
+}
+
+func (p *recordingProvider) SummarizeStream(ctx context.Context, notes string, systemPrompt string, w io.Writer) (string, error) {
+	p.prompt, p.system = notes, systemPrompt
+	for _, delta := range []string{"sum", "mary"} {
+		if _, err := io.WriteString(w, delta); err != nil {
+			return "", err
+		}
+	}
+	return "summary", nil
+}
+
+func (p *recordingProvider) GenerateTitle(ctx context.Context, summary string, systemPrompt string) (string, error) {
+	p.prompt, p.system = summary, systemPrompt
+	return "title", nil
+}
+
+func (p *recordingProvider) Chat(ctx context.Context, messages []Message) (string, error) {
+	return "chat", nil
 }
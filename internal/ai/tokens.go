@@ -0,0 +1,51 @@
+package ai
+
+import "unicode"
+
+// defaultMaxInputTokens is the prompt budget for providers that do not
+// implement TokenCounter.
+const defaultMaxInputTokens = 100_000
+
+// TokenCounter is implemented by providers that can estimate prompt size and
+// know how much input they accept.
+type TokenCounter interface {
+	EstimateTokens(text string) int
+	MaxInputTokens() int
+}
+
+// EstimateTokens estimates the prompt size of text for provider.
+func EstimateTokens(provider AIProvider, text string) int {
+	if tc, ok := provider.(TokenCounter); ok {
+		return tc.EstimateTokens(text)
+	}
+	return estimateTokens(text, 1, 4)
+}
+
+// MaxInputTokens returns the prompt budget of provider, or override when positive.
+func MaxInputTokens(provider AIProvider, override int) int {
+	if override > 0 {
+		return override
+	}
+	if tc, ok := provider.(TokenCounter); ok {
+		return tc.MaxInputTokens()
+	}
+	return defaultMaxInputTokens
+}
+
+// estimateTokens approximates a token count from the number of CJK characters
+// and other characters per token. It errs on the high side.
+func estimateTokens(text string, cjkPerToken, otherPerToken float64) int {
+	var cjk, other int
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			cjk++
+		case r < 0x80:
+			other++
+		default:
+			// Other non-ASCII runes (emoji, full-width symbols) are usually split.
+			cjk += 2
+		}
+	}
+	return int(float64(cjk)/cjkPerToken+float64(other)/otherPerToken) + 1
+}
@@ -0,0 +1,259 @@
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+const stateFileName = "state.json"
+
+// Store is a local note archive that keeps one JSONL file per month (UTC).
+type Store struct {
+	dir string
+}
+
+// Interval is a half-open time range [Start, End) whose notes are fully archived.
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// State records sync progress and which time ranges the archive covers.
+type State struct {
+	UserID      string     `json:"user_id,omitempty"`
+	LastID      string     `json:"last_id,omitempty"`
+	SyncedUntil time.Time  `json:"synced_until,omitzero"`
+	Covered     []Interval `json:"covered,omitempty"`
+}
+
+// NewStore creates an archive store rooted at dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the root directory of the archive.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Put merges notes into the archive, replacing existing records with the same ID.
+func (s *Store) Put(notes []models.Note) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	byMonth := make(map[string][]models.Note)
+	for _, note := range notes {
+		key := monthKey(note.CreatedAt)
+		byMonth[key] = append(byMonth[key], note)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	for key, monthNotes := range byMonth {
+		path := s.monthPath(key)
+		existing, err := readNotes(path)
+		if err != nil {
+			return err
+		}
+
+		merged := make(map[string]models.Note, len(existing)+len(monthNotes))
+		for _, note := range existing {
+			merged[note.ID] = note
+		}
+		for _, note := range monthNotes {
+			merged[note.ID] = note
+		}
+
+		if err := writeNotes(path, merged); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Notes returns archived notes created within [start, end), oldest first.
+func (s *Store) Notes(start, end time.Time) ([]models.Note, error) {
+	var result []models.Note
+
+	first := time.Date(start.UTC().Year(), start.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+	for month := first; month.Before(end); month = month.AddDate(0, 1, 0) {
+		notes, err := readNotes(s.monthPath(monthKey(month)))
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range notes {
+			if note.CreatedAt.Before(start) || !note.CreatedAt.Before(end) {
+				continue
+			}
+			result = append(result, note)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// LoadState reads the sync state. A missing state file yields an empty state.
+func (s *Store) LoadState() (*State, error) {
+	content, err := os.ReadFile(filepath.Join(s.dir, stateFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &State{}, nil
+		}
+		return nil, fmt.Errorf("failed to read archive state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode archive state: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveState writes the sync state.
+func (s *Store) SaveState(state *State) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive state: %w", err)
+	}
+
+	path := filepath.Join(s.dir, stateFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write archive state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write archive state: %w", err)
+	}
+	return nil
+}
+
+// Covers reports whether [start, end) lies entirely within an archived interval.
+func (st *State) Covers(start, end time.Time) bool {
+	for _, interval := range st.Covered {
+		if !start.Before(interval.Start) && !end.After(interval.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddCovered records [start, end) as archived, merging overlapping intervals.
+func (st *State) AddCovered(start, end time.Time) {
+	if !start.Before(end) {
+		return
+	}
+
+	intervals := append(st.Covered, Interval{Start: start.UTC(), End: end.UTC()})
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+
+	merged := intervals[:1]
+	for _, interval := range intervals[1:] {
+		last := &merged[len(merged)-1]
+		if interval.Start.After(last.End) {
+			merged = append(merged, interval)
+			continue
+		}
+		if interval.End.After(last.End) {
+			last.End = interval.End
+		}
+	}
+	st.Covered = merged
+}
+
+func (s *Store) monthPath(key string) string {
+	return filepath.Join(s.dir, key+".jsonl")
+}
+
+func monthKey(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+func readNotes(path string) ([]models.Note, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var notes []models.Note
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var note models.Note
+		if err := json.Unmarshal(line, &note); err != nil {
+			return nil, fmt.Errorf("failed to decode archive %s: %w", filepath.Base(path), err)
+		}
+		notes = append(notes, note)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	return notes, nil
+}
+
+func writeNotes(path string, notes map[string]models.Note) error {
+	sorted := make([]models.Note, 0, len(notes))
+	for _, note := range notes {
+		sorted = append(sorted, note)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].ID < sorted[j].ID
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, note := range sorted {
+		if err := enc.Encode(note); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to encode note %s: %w", note.ID, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
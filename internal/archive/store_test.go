@@ -0,0 +1,86 @@
+package archive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestStorePutAndNotes(t *testing.T) {
+	store := NewStore(t.TempDir())
+	base := time.Date(2026, 2, 28, 22, 0, 0, 0, time.UTC)
+
+	if err := store.Put([]models.Note{
+		{ID: "b", CreatedAt: base.Add(4 * time.Hour), Text: strPtr("march")},
+		{ID: "a", CreatedAt: base, Text: strPtr("february")},
+	}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	// Re-putting a note replaces it instead of duplicating it.
+	if err := store.Put([]models.Note{
+		{ID: "a", CreatedAt: base, Text: strPtr("february edited")},
+	}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	got, err := store.Notes(base.Add(-time.Hour), base.Add(5*time.Hour))
+	if err != nil {
+		t.Fatalf("Notes() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("len(notes) = %d, want 2", len(got))
+	}
+	if got[0].ID != "a" || *got[0].Text != "february edited" || got[1].ID != "b" {
+		t.Fatalf("unexpected notes: %#v", got)
+	}
+
+	got, err = store.Notes(base.Add(time.Hour), base.Add(4*time.Hour))
+	if err != nil {
+		t.Fatalf("Notes() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("end should be exclusive, got %#v", got)
+	}
+}
+
+func TestStateCoverage(t *testing.T) {
+	store := NewStore(t.TempDir())
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	state, err := store.LoadState()
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if state.Covers(day(1), day(2)) {
+		t.Fatal("empty state should not cover anything")
+	}
+
+	state.AddCovered(day(1), day(3))
+	state.AddCovered(day(5), day(6))
+	state.AddCovered(day(3), day(5))
+	if len(state.Covered) != 1 {
+		t.Fatalf("intervals should merge, got %#v", state.Covered)
+	}
+	state.LastID = "note-1"
+
+	if err := store.SaveState(state); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	loaded, err := store.LoadState()
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+
+	if loaded.LastID != "note-1" {
+		t.Fatalf("LastID = %q", loaded.LastID)
+	}
+	if !loaded.Covers(day(2), day(6)) {
+		t.Fatal("expected merged interval to cover 2026-03-02..06")
+	}
+	if loaded.Covers(day(2), day(7)) {
+		t.Fatal("range beyond the covered interval should not be covered")
+	}
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/ai"
+	"github.com/soli0222/diary-cli/internal/config"
+	"github.com/soli0222/diary-cli/internal/i18n"
+	"github.com/soli0222/diary-cli/internal/models"
+	"github.com/soli0222/diary-cli/internal/preprocess"
+)
+
+// maxThumbnailBytes skips thumbnails that are unexpectedly large.
+const maxThumbnailBytes = 5 << 20
+
+var thumbnailHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// attachDriveFiles fills in Note.Files from drive/files/show for notes that
+// only carry file IDs. Files that cannot be fetched are skipped, as are all
+// of them for --from-export.
+func attachDriveFiles(ctx context.Context, cfg *config.Config, notes []models.Note) []models.Note {
+	client := lookupClient(cfg)
+	if client == nil {
+		return notes
+	}
+
+	for i := range notes {
+		if len(notes[i].Files) > 0 || len(notes[i].FileIDs) == 0 {
+			continue
+		}
+		for _, id := range notes[i].FileIDs {
+			file, err := client.ShowDriveFile(ctx, id)
+			if err != nil {
+				continue
+			}
+			notes[i].Files = append(notes[i].Files, *file)
+		}
+	}
+	return notes
+}
+
+func attachmentLabels(msg *i18n.Messages) preprocess.AttachmentLabels {
+	return preprocess.AttachmentLabels{
+		Image:     msg.AttachmentImage,
+		Video:     msg.AttachmentVideo,
+		Audio:     msg.AttachmentAudio,
+		File:      msg.AttachmentFile,
+		Sensitive: msg.AttachmentSensitive,
+	}
+}
+
+// collectThumbnails downloads thumbnails of up to limit non-sensitive images
+// for multimodal summaries. Thumbnails that fail to download are skipped.
+func collectThumbnails(ctx context.Context, notes []models.Note, limit int) []ai.Image {
+	var images []ai.Image
+	for _, n := range notes {
+		for _, f := range n.Files {
+			if len(images) >= limit {
+				return images
+			}
+			if f.IsSensitive || !strings.HasPrefix(f.Type, "image/") || f.ThumbnailURL == nil || *f.ThumbnailURL == "" {
+				continue
+			}
+			image, err := downloadThumbnail(ctx, *f.ThumbnailURL)
+			if err != nil {
+				continue
+			}
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+func downloadThumbnail(ctx context.Context, url string) (ai.Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return ai.Image{}, err
+	}
+	resp, err := thumbnailHTTPClient.Do(req)
+	if err != nil {
+		return ai.Image{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return ai.Image{}, fmt.Errorf("thumbnail returned status %d", resp.StatusCode)
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !ai.SupportedImageType(mediaType) {
+		return ai.Image{}, fmt.Errorf("unsupported thumbnail type %q", resp.Header.Get("Content-Type"))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxThumbnailBytes+1))
+	if err != nil {
+		return ai.Image{}, err
+	}
+	if len(data) > maxThumbnailBytes {
+		return ai.Image{}, fmt.Errorf("thumbnail exceeds %d bytes", maxThumbnailBytes)
+	}
+	return ai.Image{MediaType: mediaType, Data: data}, nil
+}
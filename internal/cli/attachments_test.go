@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+func TestCollectThumbnails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok.webp":
+			w.Header().Set("Content-Type", "image/webp")
+			_, _ = w.Write([]byte("webp"))
+		case "/tiff":
+			w.Header().Set("Content-Type", "image/tiff")
+			_, _ = w.Write([]byte("tiff"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	url := func(path string) *string {
+		u := server.URL + path
+		return &u
+	}
+	notes := []models.Note{
+		{ID: "1", Files: []models.DriveFile{
+			{Type: "image/jpeg", ThumbnailURL: url("/ok.webp")},
+			{Type: "image/jpeg", ThumbnailURL: url("/ok.webp"), IsSensitive: true},
+			{Type: "video/mp4", ThumbnailURL: url("/ok.webp")},
+			{Type: "image/tiff", ThumbnailURL: url("/tiff")},
+			{Type: "image/png", ThumbnailURL: url("/missing")},
+			{Type: "image/png"},
+		}},
+		{ID: "2", Files: []models.DriveFile{
+			{Type: "image/png", ThumbnailURL: url("/ok.webp")},
+			{Type: "image/png", ThumbnailURL: url("/ok.webp")},
+		}},
+	}
+
+	got := collectThumbnails(context.Background(), notes, 2)
+
+	if len(got) != 2 {
+		t.Fatalf("len(images) = %d, want 2", len(got))
+	}
+	for _, image := range got {
+		if image.MediaType != "image/webp" || string(image.Data) != "webp" {
+			t.Fatalf("image = %#v", image)
+		}
+	}
+}
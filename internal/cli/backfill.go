@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/soli0222/diary-cli/internal/config"
+	"github.com/soli0222/diary-cli/internal/i18n"
+)
+
+var (
+	backfillFlagFrom     string
+	backfillFlagTo       string
+	backfillFlagForce    bool
+	backfillFlagMode     string
+	backfillFlagProvider string
+
+	dateWorkflowRunner = runDiaryWorkflowForDate
+)
+
+type backfillStatus string
+
+const (
+	backfillSaved   backfillStatus = "saved"
+	backfillSkipped backfillStatus = "skipped"
+	backfillFailed  backfillStatus = "failed"
+)
+
+type backfillDayResult struct {
+	Date   time.Time
+	Status backfillStatus
+	Path   string
+	Err    error
+}
+
+func newBackfillCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "指定期間の日記をまとめて生成する",
+		RunE:  runBackfill,
+	}
+
+	cmd.Flags().StringVar(&backfillFlagFrom, "from", "", "開始日 (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&backfillFlagTo, "to", "", "終了日 (YYYY-MM-DD, 省略時は開始日と同じ)")
+	cmd.Flags().BoolVar(&backfillFlagForce, "force", false, "既存の日記ファイルも再生成する")
+	cmd.Flags().StringVar(&backfillFlagMode, "mode", writeModeMerge, "--force で再生成する日記の扱い (replace, merge, append, skip)")
+	cmd.Flags().StringVarP(&backfillFlagProvider, "provider", "p", "", "AIプロバイダ (claude, openai, gemini, local。カンマ区切りで順にフォールバック)")
+	cmd.Flags().StringVar(&flagFromExport, "from-export", "", "Misskeyのノートエクスポート(JSON)からノートを読み込む")
+	cmd.Flags().StringVar(&flagPrivacy, "privacy", "", "AIと出力に渡すノートの範囲 (strict, normal, all; 省略時は設定ファイル準拠)")
+
+	return cmd
+}
+
+func runBackfill(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	loc, err := cfg.DiaryLocation()
+	if err != nil {
+		return err
+	}
+
+	msg, err := messagesFor(cfg)
+	if err != nil {
+		return err
+	}
+
+	dates, err := resolveBackfillDates(backfillFlagFrom, backfillFlagTo, loc)
+	if err != nil {
+		return err
+	}
+	mode, err := resolveWriteMode(backfillFlagMode)
+	if err != nil {
+		return err
+	}
+
+	ctx := commandContext(cmd)
+	stderr := cmd.ErrOrStderr()
+
+	results := make([]backfillDayResult, 0, len(dates))
+	for _, date := range dates {
+		if ctx.Err() != nil {
+			break
+		}
+		result := backfillDay(cmd, cfg, msg, date, mode, stderr)
+		results = append(results, result)
+	}
+
+	return reportBackfill(stderr, msg, results, len(dates))
+}
+
+func backfillDay(cmd *cobra.Command, cfg *config.Config, msg *i18n.Messages, date time.Time, mode string, progress io.Writer) backfillDayResult {
+	relPath, err := diaryRelPath(cfg, date)
+	if err != nil {
+		return backfillDayResult{Date: date, Status: backfillFailed, Err: err}
+	}
+	outputPath := filepath.Join(cfg.Diary.OutputDir, relPath)
+	if !backfillFlagForce || mode == writeModeSkip {
+		if _, err := os.Stat(outputPath); err == nil {
+			return backfillDayResult{Date: date, Status: backfillSkipped, Path: outputPath}
+		}
+	}
+	if mode == writeModeMerge {
+		if err := checkMergeable(cfg, date); err != nil {
+			return backfillDayResult{Date: date, Status: backfillFailed, Err: err}
+		}
+	}
+
+	ctx := commandContext(cmd)
+	result, err := dateWorkflowRunner(ctx, cfg, date, backfillFlagProvider, progress, nil)
+	if err != nil {
+		return backfillDayResult{Date: date, Status: backfillFailed, Err: err}
+	}
+
+	savedPath, err := writeMarkdownDiary(ctx, cfg, result, mode)
+	if err != nil {
+		return backfillDayResult{Date: date, Status: backfillFailed, Err: err}
+	}
+	if err := writeLine(progress, fmt.Sprintf(msg.Saved, savedPath)); err != nil {
+		return backfillDayResult{Date: date, Status: backfillFailed, Err: err}
+	}
+	return backfillDayResult{Date: date, Status: backfillSaved, Path: savedPath}
+}
+
+func resolveBackfillDates(from, to string, loc *time.Location) ([]time.Time, error) {
+	if from == "" {
+		return nil, fmt.Errorf("--from is required")
+	}
+	if to == "" {
+		to = from
+	}
+
+	start, err := time.ParseInLocation("2006-01-02", from, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --from (expected YYYY-MM-DD): %w", err)
+	}
+	end, err := time.ParseInLocation("2006-01-02", to, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --to (expected YYYY-MM-DD): %w", err)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("--to must not be before --from")
+	}
+
+	var dates []time.Time
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, normalizeToLocalMidnight(d, loc))
+	}
+	return dates, nil
+}
+
+func reportBackfill(w io.Writer, msg *i18n.Messages, results []backfillDayResult, total int) error {
+	var saved, skipped, failed int
+
+	if err := writeLine(w, "\n"+msg.BackfillResult); err != nil {
+		return err
+	}
+	for _, r := range results {
+		line := fmt.Sprintf("  %s %s", r.Date.Format("2006-01-02"), r.Status)
+		switch r.Status {
+		case backfillSaved:
+			saved++
+		case backfillSkipped:
+			skipped++
+			line += msg.BackfillExists
+		case backfillFailed:
+			failed++
+			line += fmt.Sprintf(": %v", r.Err)
+		}
+		if err := writeLine(w, line); err != nil {
+			return err
+		}
+	}
+
+	summary := fmt.Sprintf(msg.BackfillTotals, saved, skipped, failed)
+	if remaining := total - len(results); remaining > 0 {
+		summary += fmt.Sprintf(msg.BackfillPending, remaining)
+	}
+	if err := writeLine(w, summary); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("backfill failed for %d day(s)", failed)
+	}
+	if remaining := total - len(results); remaining > 0 {
+		return fmt.Errorf("backfill interrupted with %d day(s) remaining", remaining)
+	}
+	return nil
+}
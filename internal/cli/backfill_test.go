@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/soli0222/diary-cli/internal/config"
+)
+
+func TestResolveBackfillDates(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("JST", 9*60*60)
+
+	got, err := resolveBackfillDates("2026-02-27", "2026-03-02", loc)
+	if err != nil {
+		t.Fatalf("resolveBackfillDates() error = %v", err)
+	}
+
+	want := []string{"2026-02-27", "2026-02-28", "2026-03-01", "2026-03-02"}
+	if len(got) != len(want) {
+		t.Fatalf("len(dates) = %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Format("2006-01-02") != want[i] || got[i].Location() != loc {
+			t.Fatalf("dates[%d] = %v, want %s", i, got[i], want[i])
+		}
+	}
+
+	if _, err := resolveBackfillDates("2026-03-02", "2026-03-01", loc); err == nil {
+		t.Fatal("expected error for reversed range, got nil")
+	}
+	if _, err := resolveBackfillDates("", "", loc); err == nil {
+		t.Fatal("expected error for missing --from, got nil")
+	}
+}
+
+func TestRunBackfillSkipsExistingAndReportsFailures(t *testing.T) {
+	originalLoadConfig := loadConfig
+	originalDateWorkflowRunner := dateWorkflowRunner
+	originalFrom, originalTo := backfillFlagFrom, backfillFlagTo
+	originalForce := backfillFlagForce
+	defer func() {
+		loadConfig = originalLoadConfig
+		dateWorkflowRunner = originalDateWorkflowRunner
+		backfillFlagFrom, backfillFlagTo = originalFrom, originalTo
+		backfillFlagForce = originalForce
+	}()
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "2026", "0301.md")
+	if err := os.MkdirAll(filepath.Dir(existing), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(existing, []byte("handwritten"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loadConfig = func() (*config.Config, error) {
+		cfg := &config.Config{}
+		cfg.Diary.OutputDir = dir
+		cfg.Diary.Timezone = "UTC"
+		return cfg, nil
+	}
+
+	var called []string
+	dateWorkflowRunner = func(ctx context.Context, cfg *config.Config, targetDate time.Time, providerName string, progress, stream io.Writer) (*diaryRunResult, error) {
+		called = append(called, targetDate.Format("2006-01-02"))
+		if targetDate.Day() == 3 {
+			return nil, errors.New("boom")
+		}
+		start, end := resolveDiaryWindow(targetDate, 5)
+		return &diaryRunResult{TargetDate: targetDate, StartTime: start, EndTime: end, Title: "title", Summary: "summary"}, nil
+	}
+	backfillFlagFrom, backfillFlagTo = "2026-03-01", "2026-03-03"
+	backfillFlagForce = false
+
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+
+	err := runBackfill(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "1 day(s)") {
+		t.Fatalf("err = %v, want failure count", err)
+	}
+
+	if strings.Join(called, ",") != "2026-03-02,2026-03-03" {
+		t.Fatalf("workflow called for %v", called)
+	}
+	content, err := os.ReadFile(existing)
+	if err != nil || string(content) != "handwritten" {
+		t.Fatalf("existing diary was modified: %q, %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026", "0302.md")); err != nil {
+		t.Fatalf("expected 0302.md to be written: %v", err)
+	}
+	for _, want := range []string{"2026-03-01 skipped", "2026-03-02 saved", "2026-03-03 failed: boom", "保存: 1, スキップ: 1, 失敗: 1"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Fatalf("stderr = %q, want %q", stderr.String(), want)
+		}
+	}
+}
+
+func TestRunBackfillForceUsesMode(t *testing.T) {
+	originalLoadConfig := loadConfig
+	originalDateWorkflowRunner := dateWorkflowRunner
+	originalFrom, originalTo := backfillFlagFrom, backfillFlagTo
+	originalForce, originalMode := backfillFlagForce, backfillFlagMode
+	defer func() {
+		loadConfig = originalLoadConfig
+		dateWorkflowRunner = originalDateWorkflowRunner
+		backfillFlagFrom, backfillFlagTo = originalFrom, originalTo
+		backfillFlagForce, backfillFlagMode = originalForce, originalMode
+	}()
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "2026", "0301.md")
+	if err := os.MkdirAll(filepath.Dir(existing), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(existing, []byte("handwritten"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loadConfig = func() (*config.Config, error) {
+		cfg := &config.Config{}
+		cfg.Diary.OutputDir = dir
+		cfg.Diary.Timezone = "UTC"
+		return cfg, nil
+	}
+	calls := 0
+	dateWorkflowRunner = func(ctx context.Context, cfg *config.Config, targetDate time.Time, providerName string, progress, stream io.Writer) (*diaryRunResult, error) {
+		calls++
+		start, end := resolveDiaryWindow(targetDate, 5)
+		return &diaryRunResult{TargetDate: targetDate, StartTime: start, EndTime: end, Title: "title", Summary: "summary"}, nil
+	}
+	backfillFlagFrom, backfillFlagTo = "2026-03-01", "2026-03-01"
+	backfillFlagForce = true
+
+	cmd := &cobra.Command{}
+	cmd.SetErr(&bytes.Buffer{})
+
+	backfillFlagMode = writeModeMerge
+	if err := runBackfill(cmd, nil); err == nil {
+		t.Fatal("merging into a diary without markers should fail")
+	}
+	if calls != 0 {
+		t.Fatalf("workflow called %d times before the merge check", calls)
+	}
+
+	backfillFlagMode = writeModeReplace
+	if err := runBackfill(cmd, nil); err != nil {
+		t.Fatalf("runBackfill(replace) error = %v", err)
+	}
+	content, err := os.ReadFile(existing)
+	if err != nil || !strings.Contains(string(content), "summary") {
+		t.Fatalf("diary was not replaced: %q, %v", content, err)
+	}
+}
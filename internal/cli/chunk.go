@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/soli0222/diary-cli/internal/ai"
+	"github.com/soli0222/diary-cli/internal/models"
+	"github.com/soli0222/diary-cli/internal/preprocess"
+)
+
+// summaryChunks turns each group into a prompt chunk. Groups whose formatted
+// notes do not fit are split into consecutive parts labelled "label (i/n)";
+// a single note that does not fit on its own still gets its own part.
+func summaryChunks(groups []preprocess.TimeGroup, format func([]preprocess.TimeGroup) string, fits func(string) bool) []ai.PromptChunk {
+	var chunks []ai.PromptChunk
+	for _, g := range groups {
+		parts := [][]models.Note{g.Notes}
+		if !fits(format([]preprocess.TimeGroup{g})) {
+			parts = splitNotes(g, format, fits)
+		}
+
+		for i, notes := range parts {
+			label := g.Label
+			if len(parts) > 1 {
+				label = fmt.Sprintf("%s (%d/%d)", g.Label, i+1, len(parts))
+			}
+			chunks = append(chunks, ai.PromptChunk{
+				Label:     label,
+				NoteCount: len(notes),
+				Notes:     format([]preprocess.TimeGroup{{Label: label, Notes: notes}}),
+			})
+		}
+	}
+	return chunks
+}
+
+// splitNotes greedily packs the notes of g into consecutive parts that fit.
+func splitNotes(g preprocess.TimeGroup, format func([]preprocess.TimeGroup) string, fits func(string) bool) [][]models.Note {
+	var parts [][]models.Note
+	var current []models.Note
+	for _, n := range g.Notes {
+		candidate := append(current[:len(current):len(current)], n)
+		if len(current) > 0 && !fits(format([]preprocess.TimeGroup{{Label: g.Label, Notes: candidate}})) {
+			parts = append(parts, current)
+			candidate = []models.Note{n}
+		}
+		current = candidate
+	}
+	if len(current) > 0 {
+		parts = append(parts, current)
+	}
+	return parts
+}
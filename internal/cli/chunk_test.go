@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/models"
+	"github.com/soli0222/diary-cli/internal/preprocess"
+)
+
+func TestSummaryChunks(t *testing.T) {
+	base := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
+	note := func(id, text string) models.Note {
+		return models.Note{ID: id, CreatedAt: base, Text: &text}
+	}
+	groups := []preprocess.TimeGroup{
+		{Label: "朝", Notes: []models.Note{note("1", "a")}},
+		{Label: "夜", Notes: []models.Note{note("2", "b"), note("3", "c"), note("4", "d"), note("5", strings.Repeat("x", 50))}},
+	}
+	format := func(groups []preprocess.TimeGroup) string {
+		return preprocess.FormatGroupedNotes(groups, time.UTC)
+	}
+	fits := func(notes string) bool { return len(notes) <= 40 }
+
+	chunks := summaryChunks(groups, format, fits)
+
+	var labels []string
+	for _, c := range chunks {
+		labels = append(labels, c.Label)
+	}
+	want := []string{"朝", "夜 (1/3)", "夜 (2/3)", "夜 (3/3)"}
+	if strings.Join(labels, ",") != strings.Join(want, ",") {
+		t.Fatalf("labels = %v, want %v", labels, want)
+	}
+	if chunks[1].NoteCount != 2 || chunks[2].NoteCount != 1 || chunks[3].NoteCount != 1 {
+		t.Fatalf("chunks = %#v", chunks)
+	}
+	if !strings.HasPrefix(chunks[1].Notes, "## 夜 (1/3)\n- [00:00] b\n- [00:00] c\n") {
+		t.Fatalf("chunks[1].Notes = %q", chunks[1].Notes)
+	}
+}
@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/soli0222/diary-cli/internal/ai"
+	"github.com/soli0222/diary-cli/internal/config"
+	"github.com/soli0222/diary-cli/internal/generator"
+	"github.com/soli0222/diary-cli/internal/i18n"
+)
+
+var (
+	digestFlagWeek     string
+	digestFlagMonth    string
+	digestFlagProvider string
+)
+
+const (
+	digestWeekly  = "weekly"
+	digestMonthly = "monthly"
+)
+
+type digestPeriod struct {
+	Kind    string
+	Label   string
+	Start   time.Time
+	End     time.Time
+	RelPath string
+}
+
+func newDigestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "生成済みの日記から週間・月間の振り返りを生成する",
+		RunE:  runDigest,
+	}
+
+	cmd.Flags().StringVar(&digestFlagWeek, "week", "", "対象週 (YYYY-Www, ISO週番号)")
+	cmd.Flags().StringVar(&digestFlagMonth, "month", "", "対象月 (YYYY-MM)")
+	cmd.Flags().StringVarP(&digestFlagProvider, "provider", "p", "", "AIプロバイダ (claude, openai, gemini, local。カンマ区切りで順にフォールバック)")
+
+	return cmd
+}
+
+func runDigest(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(cfg.Diary.OutputDir) == "" {
+		return fmt.Errorf("diary.output_dir is required for digest")
+	}
+
+	loc, err := cfg.DiaryLocation()
+	if err != nil {
+		return err
+	}
+
+	msg, err := messagesFor(cfg)
+	if err != nil {
+		return err
+	}
+
+	period, err := resolveDigestPeriod(digestFlagWeek, digestFlagMonth, loc)
+	if err != nil {
+		return err
+	}
+
+	stderr := cmd.ErrOrStderr()
+
+	diaries, count, err := collectDailyDiaries(cfg.Diary.OutputDir, cfg.Diary.PathPattern, period)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("no diaries found for %s", period.Label)
+	}
+	if err := writeLine(stderr, fmt.Sprintf(msg.DigestLoaded, period.Label, count)); err != nil {
+		return err
+	}
+
+	providerNames := resolveProviderNames(cfg, digestFlagProvider)
+	if len(providerNames) == 0 {
+		return fmt.Errorf("ai.default_provider か --provider を指定してください")
+	}
+
+	prompts, err := loadPrompts(cfg)
+	if err != nil {
+		return err
+	}
+	redactor, err := redactorFor(cfg)
+	if err != nil {
+		return err
+	}
+	if redactor != nil {
+		diaries = redactor.Redact(diaries)
+	}
+	promptData := ai.PromptData{
+		Date:    period.Label,
+		Author:  cfg.Diary.Author,
+		Period:  period.Label,
+		Diaries: diaries,
+	}
+
+	ctx := commandContext(cmd)
+	provider, err := buildProviderChain(ctx, providerNames, cfg, msg, stderr)
+	if err != nil {
+		return err
+	}
+	summary, err := ai.GenerateDigest(ctx, provider, prompts, promptData)
+	if err != nil {
+		return err
+	}
+	promptData.Summary = summary
+	title, err := ai.GenerateTitle(ctx, provider, prompts, promptData)
+	if err != nil {
+		return err
+	}
+	if redactor != nil && cfg.Redaction.Restore {
+		summary = redactor.Restore(summary)
+		title = redactor.Restore(title)
+	}
+
+	outputPath, err := saveDigest(cfg, msg, period, title, summary)
+	if err != nil {
+		return err
+	}
+	return writeLine(stderr, fmt.Sprintf(msg.Saved, outputPath))
+}
+
+func resolveDigestPeriod(week, month string, loc *time.Location) (digestPeriod, error) {
+	week = strings.TrimSpace(week)
+	month = strings.TrimSpace(month)
+
+	switch {
+	case week != "" && month != "":
+		return digestPeriod{}, errors.New("--week と --month は同時に指定できません")
+	case week != "":
+		return resolveWeekPeriod(week, loc)
+	case month != "":
+		return resolveMonthPeriod(month, loc)
+	default:
+		return digestPeriod{}, errors.New("--week か --month を指定してください")
+	}
+}
+
+func resolveWeekPeriod(value string, loc *time.Location) (digestPeriod, error) {
+	yearPart, weekPart, ok := strings.Cut(strings.ToUpper(value), "-W")
+	if !ok {
+		return digestPeriod{}, fmt.Errorf("invalid week format (expected YYYY-Www): %s", value)
+	}
+	year, err := strconv.Atoi(yearPart)
+	if err != nil || len(yearPart) != 4 {
+		return digestPeriod{}, fmt.Errorf("invalid week format (expected YYYY-Www): %s", value)
+	}
+	week, err := strconv.Atoi(weekPart)
+	if err != nil || week < 1 || week > 53 {
+		return digestPeriod{}, fmt.Errorf("invalid week format (expected YYYY-Www): %s", value)
+	}
+
+	// January 4th always falls in ISO week 1.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	start := jan4.AddDate(0, 0, -offset+(week-1)*7)
+	if y, w := start.ISOWeek(); y != year || w != week {
+		return digestPeriod{}, fmt.Errorf("week %d does not exist in %d", week, year)
+	}
+
+	label := fmt.Sprintf("%04d-W%02d", year, week)
+	return digestPeriod{
+		Kind:    digestWeekly,
+		Label:   label,
+		Start:   start,
+		End:     start.AddDate(0, 0, 6),
+		RelPath: filepath.Join(fmt.Sprintf("%04d", year), "weekly", fmt.Sprintf("W%02d.md", week)),
+	}, nil
+}
+
+func resolveMonthPeriod(value string, loc *time.Location) (digestPeriod, error) {
+	start, err := time.ParseInLocation("2006-01", value, loc)
+	if err != nil {
+		return digestPeriod{}, fmt.Errorf("invalid month format (expected YYYY-MM): %w", err)
+	}
+
+	return digestPeriod{
+		Kind:    digestMonthly,
+		Label:   start.Format("2006-01"),
+		Start:   start,
+		End:     start.AddDate(0, 1, -1),
+		RelPath: filepath.Join(start.Format("2006"), "monthly", start.Format("01")+".md"),
+	}, nil
+}
+
+func collectDailyDiaries(outputDir, pathPattern string, period digestPeriod) (string, int, error) {
+	var (
+		sb    strings.Builder
+		count int
+	)
+
+	for d := period.Start; !d.After(period.End); d = d.AddDate(0, 0, 1) {
+		relPath, err := generator.DiaryPath(pathPattern, d)
+		if err != nil {
+			return "", 0, err
+		}
+		content, err := os.ReadFile(filepath.Join(outputDir, relPath))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return "", 0, fmt.Errorf("failed to read diary: %w", err)
+		}
+
+		body := generator.StripFrontMatter(string(content))
+		if body == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "### %s\n%s\n\n", d.Format("2006-01-02"), body)
+		count++
+	}
+
+	return sb.String(), count, nil
+}
+
+func saveDigest(cfg *config.Config, msg *i18n.Messages, period digestPeriod, title, summary string) (string, error) {
+	heading, category := msg.WeeklyHeading, msg.WeeklyCategory
+	if period.Kind == digestMonthly {
+		heading, category = msg.MonthlyHeading, msg.MonthlyCategory
+	}
+	markdown := generator.BuildDigestMarkdown(period.Start, cfg.Diary.Author, period.Label, heading, category, title, summary)
+
+	outputPath := filepath.Join(cfg.Diary.OutputDir, period.RelPath)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, []byte(markdown), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	return outputPath, nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveDigestPeriod(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("JST", 9*60*60)
+
+	t.Run("iso week", func(t *testing.T) {
+		got, err := resolveDigestPeriod("2026-W41", "", loc)
+		if err != nil {
+			t.Fatalf("resolveDigestPeriod() error = %v", err)
+		}
+		if !got.Start.Equal(time.Date(2026, 10, 5, 0, 0, 0, 0, loc)) || !got.End.Equal(time.Date(2026, 10, 11, 0, 0, 0, 0, loc)) {
+			t.Fatalf("range = %v - %v", got.Start, got.End)
+		}
+		if got.RelPath != filepath.Join("2026", "weekly", "W41.md") {
+			t.Fatalf("RelPath = %q", got.RelPath)
+		}
+	})
+
+	t.Run("week 1 starting in previous year", func(t *testing.T) {
+		got, err := resolveDigestPeriod("2026-W01", "", loc)
+		if err != nil {
+			t.Fatalf("resolveDigestPeriod() error = %v", err)
+		}
+		if !got.Start.Equal(time.Date(2025, 12, 29, 0, 0, 0, 0, loc)) {
+			t.Fatalf("Start = %v", got.Start)
+		}
+	})
+
+	t.Run("month", func(t *testing.T) {
+		got, err := resolveDigestPeriod("", "2026-02", loc)
+		if err != nil {
+			t.Fatalf("resolveDigestPeriod() error = %v", err)
+		}
+		if !got.End.Equal(time.Date(2026, 2, 28, 0, 0, 0, 0, loc)) {
+			t.Fatalf("End = %v", got.End)
+		}
+		if got.RelPath != filepath.Join("2026", "monthly", "02.md") {
+			t.Fatalf("RelPath = %q", got.RelPath)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tt := range [][2]string{{"", ""}, {"2026-W41", "2026-10"}, {"2026-41", ""}, {"2026-W54", ""}, {"", "2026/10"}} {
+			if _, err := resolveDigestPeriod(tt[0], tt[1], loc); err == nil {
+				t.Fatalf("resolveDigestPeriod(%q, %q) error = nil", tt[0], tt[1])
+			}
+		}
+	})
+}
+
+func TestCollectDailyDiaries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	loc := time.UTC
+	period, err := resolveDigestPeriod("", "2026-03", loc)
+	if err != nil {
+		t.Fatalf("resolveDigestPeriod() error = %v", err)
+	}
+
+	write := func(rel, content string) {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	write(filepath.Join("2026", "0302.md"), "---\ntitle: 2026-03-02\n---\n\n# 月曜\n\n本文A\n")
+	write(filepath.Join("2026", "0315.md"), "---\ntitle: 2026-03-15\n---\n\n# 日曜\n\n本文B\n")
+	write(filepath.Join("2026", "0401.md"), "---\ntitle: 2026-04-01\n---\n\n# 範囲外\n")
+
+	got, count, err := collectDailyDiaries(dir, "", period)
+	if err != nil {
+		t.Fatalf("collectDailyDiaries() error = %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("count = %d, want 2", count)
+	}
+	if !strings.Contains(got, "### 2026-03-02\n# 月曜\n\n本文A") || !strings.Contains(got, "### 2026-03-15\n# 日曜") {
+		t.Fatalf("unexpected digest input: %q", got)
+	}
+	if strings.Contains(got, "範囲外") || strings.Contains(got, "title:") {
+		t.Fatalf("digest input should exclude other months and front matter: %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/soli0222/diary-cli/internal/config"
+)
+
+var (
+	editFlagPush bool
+
+	editorRunner = openInEditor
+)
+
+func newEditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "対象日の日記をエディタで開く",
+		RunE:  runEdit,
+	}
+
+	cmd.Flags().BoolVar(&editFlagPush, "push", false, "エディタを閉じた後にgit commit & pushする")
+
+	return cmd
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	date, err := resolveConfiguredDate(cfg)
+	if err != nil {
+		return err
+	}
+	relPath, err := diaryRelPath(cfg, date)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(cfg.Diary.OutputDir, relPath)
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("diary not found: %s (run `diary-cli run` first)", path)
+		}
+		return fmt.Errorf("failed to check diary: %w", err)
+	}
+
+	if err := editorRunner(cfg.Diary.Editor, path); err != nil {
+		return err
+	}
+
+	if editFlagPush {
+		return diaryPusher(cmd.OutOrStdout(), cfg, date)
+	}
+	return nil
+}
+
+// openInEditor runs editor on path attached to the terminal and waits for it to exit.
+// editor may include arguments, e.g. "code --wait".
+func openInEditor(editor, path string) error {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		fields = strings.Fields(config.EnvOrDefault("EDITOR", "vim"))
+	}
+
+	c := exec.Command(fields[0], append(fields[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", fields[0], err)
+	}
+	return nil
+}
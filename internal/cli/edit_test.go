@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/soli0222/diary-cli/internal/config"
+)
+
+func TestRunEditOpensDiaryAndPushes(t *testing.T) {
+	originalLoadConfig := loadConfig
+	originalEditorRunner := editorRunner
+	originalDiaryPusher := diaryPusher
+	originalFlagDate := flagDate
+	originalEditFlagPush := editFlagPush
+	defer func() {
+		loadConfig = originalLoadConfig
+		editorRunner = originalEditorRunner
+		diaryPusher = originalDiaryPusher
+		flagDate = originalFlagDate
+		editFlagPush = originalEditFlagPush
+	}()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2026", "0404.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte("diary"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loadConfig = func() (*config.Config, error) {
+		cfg := &config.Config{}
+		cfg.Diary.OutputDir = dir
+		cfg.Diary.Editor = "code --wait"
+		cfg.Diary.Timezone = "UTC"
+		cfg.Diary.DayStartHour = 5
+		return cfg, nil
+	}
+
+	var calls []string
+	editorRunner = func(editor, p string) error {
+		calls = append(calls, "edit "+editor+" "+p)
+		return nil
+	}
+	diaryPusher = func(w io.Writer, cfg *config.Config, date time.Time) error {
+		calls = append(calls, "push "+date.Format("2006-01-02"))
+		return nil
+	}
+	flagDate = "2026-04-04"
+	editFlagPush = true
+
+	if err := runEdit(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("runEdit() error = %v", err)
+	}
+
+	want := []string{"edit code --wait " + path, "push 2026-04-04"}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+
+	flagDate = "2026-04-05"
+	if err := runEdit(&cobra.Command{}, nil); err == nil || !strings.Contains(err.Error(), "diary not found") {
+		t.Fatalf("err = %v, want diary not found", err)
+	}
+}
+
+func TestRunRunEditsGeneratedDiary(t *testing.T) {
+	originalLoadConfig := loadConfig
+	originalWorkflowRunner := diaryWorkflowRunner
+	originalEditorRunner := editorRunner
+	originalDiaryPusher := diaryPusher
+	originalFlagOutput := flagOutput
+	originalFlagEdit, originalFlagPush := flagEdit, flagPush
+	defer func() {
+		loadConfig = originalLoadConfig
+		diaryWorkflowRunner = originalWorkflowRunner
+		editorRunner = originalEditorRunner
+		diaryPusher = originalDiaryPusher
+		flagOutput = originalFlagOutput
+		flagEdit, flagPush = originalFlagEdit, originalFlagPush
+	}()
+
+	dir := t.TempDir()
+	loadConfig = func() (*config.Config, error) {
+		cfg := &config.Config{}
+		cfg.Diary.OutputDir = dir
+		cfg.Diary.Editor = "vim"
+		return cfg, nil
+	}
+	diaryWorkflowRunner = func(ctx context.Context, cfg *config.Config, providerName string, progress, stream io.Writer) (*diaryRunResult, error) {
+		date := time.Date(2026, 4, 4, 0, 0, 0, 0, time.UTC)
+		return &diaryRunResult{TargetDate: date, StartTime: date.Add(5 * time.Hour), Title: "title", Summary: "summary"}, nil
+	}
+
+	var edited string
+	pushed := false
+	editorRunner = func(editor, path string) error {
+		edited = path
+		return nil
+	}
+	diaryPusher = func(w io.Writer, cfg *config.Config, date time.Time) error {
+		pushed = true
+		return nil
+	}
+	flagOutput = outputMarkdown
+	flagEdit, flagPush = true, true
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := runRun(cmd, nil); err != nil {
+		t.Fatalf("runRun() error = %v", err)
+	}
+
+	if edited != filepath.Join(dir, "2026", "0404.md") {
+		t.Fatalf("edited = %q, want generated diary", edited)
+	}
+	if !pushed {
+		t.Fatal("expected diary to be pushed after editing")
+	}
+
+	flagOutput = outputJSON
+	if err := runRun(cmd, nil); err == nil {
+		t.Fatal("expected error for --edit with json output, got nil")
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/generator"
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+// maxGalleryImageBytes skips images too large to keep next to a diary.
+const maxGalleryImageBytes = 20 << 20
+
+var galleryHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+var galleryNameSanitizer = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// downloadGallery saves the non-sensitive images attached to notes into the
+// gallery directory of the diary at relPath and returns them in note order.
+// Images that fail to download are skipped; images already on disk are reused.
+func downloadGallery(ctx context.Context, outputDir, relPath string, notes []models.Note) ([]generator.GalleryImage, error) {
+	dir := filepath.Join(outputDir, generator.GalleryDir(relPath))
+
+	var images []generator.GalleryImage
+	for _, n := range notes {
+		for _, f := range n.Files {
+			if f.IsSensitive || !strings.HasPrefix(f.Type, "image/") || f.URL == "" {
+				continue
+			}
+			name := galleryFileName(f)
+			if name == "" {
+				continue
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, fmt.Errorf("failed to create gallery directory: %w", err)
+			}
+			if err := downloadGalleryImage(ctx, f.URL, filepath.Join(dir, name)); err != nil {
+				continue
+			}
+			alt := f.Name
+			if f.Comment != nil && strings.TrimSpace(*f.Comment) != "" {
+				alt = *f.Comment
+			}
+			images = append(images, generator.NewGalleryImage(relPath, name, alt))
+		}
+	}
+	return images, nil
+}
+
+// galleryFileName names the saved image after its drive file ID so reruns
+// reuse the same file.
+func galleryFileName(f models.DriveFile) string {
+	id := galleryNameSanitizer.ReplaceAllString(f.ID, "")
+	if id == "" {
+		return ""
+	}
+	ext := strings.ToLower(filepath.Ext(f.Name))
+	if ext == "" || galleryNameSanitizer.MatchString(ext[1:]) {
+		ext = ""
+		if exts, err := mime.ExtensionsByType(f.Type); err == nil && len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+	return id + ext
+}
+
+func downloadGalleryImage(ctx context.Context, url, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := galleryHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("image returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxGalleryImageBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxGalleryImageBytes {
+		return fmt.Errorf("image exceeds %d bytes", maxGalleryImageBytes)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
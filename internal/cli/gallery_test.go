@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+func TestDownloadGallery(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("image:" + r.URL.Path))
+	}))
+	defer server.Close()
+
+	comment := "朝ごはん"
+	notes := []models.Note{
+		{ID: "n1", Files: []models.DriveFile{
+			{ID: "f1", Name: "IMG_0001.JPG", Type: "image/jpeg", Comment: &comment, URL: server.URL + "/f1"},
+			{ID: "f2", Name: "secret.png", Type: "image/png", IsSensitive: true, URL: server.URL + "/f2"},
+			{ID: "f3", Name: "clip.mp4", Type: "video/mp4", URL: server.URL + "/f3"},
+			{ID: "f4", Name: "gone.png", Type: "image/png", URL: server.URL + "/missing"},
+		}},
+		{ID: "n2", Files: []models.DriveFile{
+			{ID: "f5", Name: "noext", Type: "image/png", URL: server.URL + "/f5"},
+		}},
+	}
+
+	outputDir := t.TempDir()
+	relPath := filepath.Join("2026", "0215.md")
+	images, err := downloadGallery(context.Background(), outputDir, relPath, notes)
+	if err != nil {
+		t.Fatalf("downloadGallery() error = %v", err)
+	}
+
+	if len(images) != 2 {
+		t.Fatalf("images = %#v, want 2", images)
+	}
+	if images[0].Path != "0215/f1.jpg" || images[0].Alt != "朝ごはん" {
+		t.Fatalf("images[0] = %#v", images[0])
+	}
+	if images[1].Path != "0215/f5.png" || images[1].Alt != "noext" {
+		t.Fatalf("images[1] = %#v", images[1])
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "2026", "0215", "f1.jpg"))
+	if err != nil || string(data) != "image:/f1" {
+		t.Fatalf("saved image = %q, %v", data, err)
+	}
+
+	requests = 0
+	if _, err := downloadGallery(context.Background(), outputDir, relPath, notes); err != nil {
+		t.Fatalf("downloadGallery() rerun error = %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("rerun made %d requests, want only the failed image to be retried", requests)
+	}
+}
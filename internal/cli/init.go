@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/soli0222/diary-cli/internal/ai"
 	"github.com/soli0222/diary-cli/internal/config"
 )
 
@@ -62,11 +63,16 @@ func runInit(cmd *cobra.Command, args []string) error {
 	geminiAPIKey := prompt(scanner, "Gemini APIキー", "")
 	geminiModel := prompt(scanner, "Geminiモデル", "gemini-3.1-flash-preview")
 
+	fmt.Println("\n[Local (Ollama など)]")
+	localBaseURL := prompt(scanner, "ローカルLLMのベースURL", ai.LocalDefaultBaseURL)
+	localModel := prompt(scanner, "ローカルLLMのモデル (任意)", "")
+
 	fmt.Println("\n[Diary]")
 	outputDir := prompt(scanner, "出力先ディレクトリ", "./diary")
 	author := prompt(scanner, "author", config.EnvOrDefault("USER", "Soli"))
 	editor := prompt(scanner, "editor", config.EnvOrDefault("EDITOR", "vim"))
 	timezone := prompt(scanner, "timezone", "Asia/Tokyo")
+	language := prompt(scanner, "language (ja, en)", "ja")
 
 	fmt.Println("\n[Summaly]")
 	summalyEndpoint := prompt(scanner, "Summalyエンドポイント (任意)", "")
@@ -89,19 +95,23 @@ ai:
   gemini:
     api_key: "%s"
     model: "%s"
+  local:
+    base_url: "%s"
+    model: "%s"
 
 diary:
   output_dir: "%s"
   author: "%s"
   editor: "%s"
   timezone: "%s"
+  language: "%s"
 
 summaly:
   endpoint: "%s"
 
 discord:
   webhook_url: "%s"
-`, instanceURL, token, defaultProvider, claudeAPIKey, claudeModel, openAIAPIKey, openAIModel, geminiAPIKey, geminiModel, outputDir, author, editor, timezone, summalyEndpoint, webhookURL)
+`, instanceURL, token, defaultProvider, claudeAPIKey, claudeModel, openAIAPIKey, openAIModel, geminiAPIKey, geminiModel, localBaseURL, localModel, outputDir, author, editor, timezone, language, summalyEndpoint, webhookURL)
 
 	if err := os.MkdirAll(configDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
@@ -2,13 +2,20 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/soli0222/diary-cli/internal/config"
+	"github.com/soli0222/diary-cli/internal/generator"
 	"github.com/soli0222/diary-cli/internal/git"
 )
 
+var diaryPusher = pushDiary
+
 func newPushCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "push",
@@ -23,26 +30,44 @@ func runPush(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	loc, err := cfg.DiaryLocation()
+	date, err := resolveConfiguredDate(cfg)
 	if err != nil {
 		return err
 	}
 
-	date, err := resolveDate(loc)
+	return diaryPusher(cmd.OutOrStdout(), cfg, date)
+}
+
+// pushDiary commits and pushes the diary for date in diary.output_dir.
+func pushDiary(w io.Writer, cfg *config.Config, date time.Time) error {
+	msg, err := messagesFor(cfg)
 	if err != nil {
 		return err
 	}
 
 	dateStr := date.Format("2006-01-02")
-	filename := date.Format("0102") + ".md"
-	filePath := filepath.Join(date.Format("2006"), filename)
+	filePath, err := diaryRelPath(cfg, date)
+	if err != nil {
+		return err
+	}
 
-	fmt.Printf("📤 %s の日記をpushします\n", dateStr)
+	if err := writeLine(w, fmt.Sprintf(msg.PushStart, dateStr)); err != nil {
+		return err
+	}
+
+	var extraPaths []string
+	if galleryDir := generator.GalleryDir(filePath); isDir(filepath.Join(cfg.Diary.OutputDir, galleryDir)) {
+		extraPaths = append(extraPaths, galleryDir)
+	}
 
-	if err := git.CommitAndPush(cfg.Diary.OutputDir, filePath, dateStr); err != nil {
+	if err := git.CommitAndPush(cfg.Diary.OutputDir, filePath, dateStr, extraPaths...); err != nil {
 		return fmt.Errorf("git push failed: %w", err)
 	}
 
-	fmt.Println("✅ pushしました")
-	return nil
+	return writeLine(w, msg.PushDone)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
@@ -1,18 +1,23 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
-)
 
-const diaryDayStartHour = 5
+	"github.com/soli0222/diary-cli/internal/config"
+)
 
 var (
 	flagDate      string
 	flagYesterday bool
+	flagTimeout   time.Duration
 
 	Version = "dev"
 )
@@ -23,12 +28,17 @@ func NewRootCmd() *cobra.Command {
 		Short: "Misskeyノートを要約して日記ベースを生成するCLIツール",
 	}
 
-	cmd.PersistentFlags().StringVarP(&flagDate, "date", "d", "", "対象日 (YYYY-MM-DD, 明示指定時は05:00補正なし)")
+	cmd.PersistentFlags().StringVarP(&flagDate, "date", "d", "", "対象日 (YYYY-MM-DD, 明示指定時は日の開始時刻による補正なし)")
 	cmd.PersistentFlags().BoolVarP(&flagYesterday, "yesterday", "y", false, "昨日の日記を作成")
+	cmd.PersistentFlags().DurationVar(&flagTimeout, "timeout", 0, "実行全体の制限時間 (例: 10m, 0 で無制限)")
 
 	cmd.AddCommand(newInitCmd())
 	cmd.AddCommand(newRunCmd())
 	cmd.AddCommand(newSummaryCmd())
+	cmd.AddCommand(newBackfillCmd())
+	cmd.AddCommand(newDigestCmd())
+	cmd.AddCommand(newSyncCmd())
+	cmd.AddCommand(newEditCmd())
 	cmd.AddCommand(newPushCmd())
 	cmd.AddCommand(newVersionCmd())
 
@@ -36,17 +46,61 @@ func NewRootCmd() *cobra.Command {
 }
 
 func Execute() {
-	if err := NewRootCmd().Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The deadline is set once the flags are parsed, so --timeout bounds the
+	// whole command, including every request and AI call it makes.
+	var cancel context.CancelFunc = func() {}
+	cmd := NewRootCmd()
+	cmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if flagTimeout > 0 {
+			var ctx context.Context
+			ctx, cancel = context.WithTimeout(cmd.Context(), flagTimeout)
+			cmd.SetContext(ctx)
+		}
+	}
+
+	err := cmd.ExecuteContext(ctx)
+	cancel()
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && flagTimeout > 0 {
+			err = fmt.Errorf("timed out after %s: %w", flagTimeout, err)
+		}
 		fmt.Fprintln(os.Stderr, err)
+		stop()
 		os.Exit(1)
 	}
 }
 
-func resolveDate(loc *time.Location) (time.Time, error) {
-	return resolveTargetDate(time.Now(), flagDate, flagYesterday, loc)
+// commandContext returns the context of cmd, which is nil when the command
+// runs outside Execute (e.g. in tests).
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+func resolveDate(loc *time.Location, dayStartHour int) (time.Time, error) {
+	return resolveTargetDate(time.Now(), flagDate, flagYesterday, loc, dayStartHour)
+}
+
+// resolveConfiguredDate resolves the target date from the --date/--yesterday
+// flags using the configured timezone and day start hour.
+func resolveConfiguredDate(cfg *config.Config) (time.Time, error) {
+	loc, err := cfg.DiaryLocation()
+	if err != nil {
+		return time.Time{}, err
+	}
+	dayStartHour, err := cfg.DiaryDayStartHour()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return resolveDate(loc, dayStartHour)
 }
 
-func resolveTargetDate(now time.Time, dateFlag string, yesterdayFlag bool, loc *time.Location) (time.Time, error) {
+func resolveTargetDate(now time.Time, dateFlag string, yesterdayFlag bool, loc *time.Location, dayStartHour int) (time.Time, error) {
 	if loc == nil {
 		loc = now.Location()
 	}
@@ -60,7 +114,7 @@ func resolveTargetDate(now time.Time, dateFlag string, yesterdayFlag bool, loc *
 	}
 
 	base := now.In(loc)
-	if base.Hour() < diaryDayStartHour {
+	if base.Hour() < dayStartHour {
 		base = base.AddDate(0, 0, -1)
 	}
 	if yesterdayFlag {
@@ -70,8 +124,8 @@ func resolveTargetDate(now time.Time, dateFlag string, yesterdayFlag bool, loc *
 	return normalizeToLocalMidnight(base, loc), nil
 }
 
-func resolveDiaryWindow(targetDate time.Time) (time.Time, time.Time) {
-	start := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), diaryDayStartHour, 0, 0, 0, targetDate.Location())
+func resolveDiaryWindow(targetDate time.Time, dayStartHour int) (time.Time, time.Time) {
+	start := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), dayStartHour, 0, 0, 0, targetDate.Location())
 	return start, start.Add(24 * time.Hour)
 }
 
@@ -12,7 +12,7 @@ func TestResolveTargetDate(t *testing.T) {
 
 	t.Run("default after 5am uses current diary date", func(t *testing.T) {
 		now := time.Date(2026, 2, 23, 8, 15, 30, 0, loc)
-		got, err := resolveTargetDate(now, "", false, loc)
+		got, err := resolveTargetDate(now, "", false, loc, 5)
 		if err != nil {
 			t.Fatalf("resolveTargetDate() error = %v", err)
 		}
@@ -24,7 +24,7 @@ func TestResolveTargetDate(t *testing.T) {
 
 	t.Run("default before 5am uses previous diary date", func(t *testing.T) {
 		now := time.Date(2026, 2, 23, 2, 15, 30, 0, loc)
-		got, err := resolveTargetDate(now, "", false, loc)
+		got, err := resolveTargetDate(now, "", false, loc, 5)
 		if err != nil {
 			t.Fatalf("resolveTargetDate() error = %v", err)
 		}
@@ -36,7 +36,7 @@ func TestResolveTargetDate(t *testing.T) {
 
 	t.Run("yesterday shifts from diary date", func(t *testing.T) {
 		now := time.Date(2026, 2, 23, 2, 15, 30, 0, loc)
-		got, err := resolveTargetDate(now, "", true, loc)
+		got, err := resolveTargetDate(now, "", true, loc, 5)
 		if err != nil {
 			t.Fatalf("resolveTargetDate() error = %v", err)
 		}
@@ -48,7 +48,7 @@ func TestResolveTargetDate(t *testing.T) {
 
 	t.Run("date flag wins over yesterday", func(t *testing.T) {
 		now := time.Date(2026, 2, 23, 2, 15, 30, 0, loc)
-		got, err := resolveTargetDate(now, "2026-02-15", true, loc)
+		got, err := resolveTargetDate(now, "2026-02-15", true, loc, 5)
 		if err != nil {
 			t.Fatalf("resolveTargetDate() error = %v", err)
 		}
@@ -58,8 +58,20 @@ func TestResolveTargetDate(t *testing.T) {
 		}
 	})
 
+	t.Run("custom day start hour keeps late evening on previous day", func(t *testing.T) {
+		now := time.Date(2026, 2, 23, 11, 30, 0, 0, loc)
+		got, err := resolveTargetDate(now, "", false, loc, 12)
+		if err != nil {
+			t.Fatalf("resolveTargetDate() error = %v", err)
+		}
+		want := time.Date(2026, 2, 22, 0, 0, 0, 0, loc)
+		if !got.Equal(want) {
+			t.Fatalf("got = %v, want %v", got, want)
+		}
+	})
+
 	t.Run("invalid date format", func(t *testing.T) {
-		if _, err := resolveTargetDate(time.Date(2026, 2, 23, 8, 15, 30, 0, loc), "2026/02/15", false, loc); err == nil {
+		if _, err := resolveTargetDate(time.Date(2026, 2, 23, 8, 15, 30, 0, loc), "2026/02/15", false, loc, 5); err == nil {
 			t.Fatal("expected error, got nil")
 		}
 	})
@@ -3,10 +3,12 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -14,9 +16,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/soli0222/diary-cli/internal/ai"
+	"github.com/soli0222/diary-cli/internal/archive"
 	"github.com/soli0222/diary-cli/internal/config"
 	"github.com/soli0222/diary-cli/internal/discord"
 	"github.com/soli0222/diary-cli/internal/generator"
+	"github.com/soli0222/diary-cli/internal/httpretry"
+	"github.com/soli0222/diary-cli/internal/i18n"
 	"github.com/soli0222/diary-cli/internal/misskey"
 	"github.com/soli0222/diary-cli/internal/models"
 	"github.com/soli0222/diary-cli/internal/preprocess"
@@ -29,10 +34,25 @@ const (
 	outputNone     = "none"
 )
 
+// Modes for writing a diary that already exists.
+const (
+	writeModeReplace = "replace"
+	writeModeMerge   = "merge"
+	writeModeAppend  = "append"
+	writeModeSkip    = "skip"
+)
+
+var errDiaryExists = errors.New("diary already exists")
+
 var (
-	flagOutput   string
-	flagDiscord  bool
-	flagProvider string
+	flagOutput     string
+	flagDiscord    bool
+	flagProvider   string
+	flagFromExport string
+	flagMode       string
+	flagEdit       bool
+	flagPush       bool
+	flagPrivacy    string
 
 	loadConfig          = config.Load
 	diaryWorkflowRunner = runDiaryWorkflow
@@ -46,6 +66,9 @@ type diaryRunResult struct {
 	Title      string
 	Summary    string
 	Notes      []models.Note
+	Sources    []generator.Source
+	// Provider is the name of the provider that wrote the summary.
+	Provider string
 }
 
 func newRunCmd() *cobra.Command {
@@ -57,7 +80,12 @@ func newRunCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&flagOutput, "output", "o", outputMarkdown, "出力形式 (markdown, summary, json, none)")
 	cmd.Flags().BoolVar(&flagDiscord, "discord", false, "Discord Webhookにも投稿する")
-	cmd.Flags().StringVarP(&flagProvider, "provider", "p", "", "AIプロバイダ (claude, openai, gemini)")
+	cmd.Flags().StringVarP(&flagProvider, "provider", "p", "", "AIプロバイダ (claude, openai, gemini, local。カンマ区切りで順にフォールバック)")
+	cmd.Flags().StringVar(&flagFromExport, "from-export", "", "Misskeyのノートエクスポート(JSON)からノートを読み込む")
+	cmd.Flags().StringVar(&flagMode, "mode", writeModeMerge, "既存の日記の扱い (replace, merge, append, skip)")
+	cmd.Flags().StringVar(&flagPrivacy, "privacy", "", "AIと出力に渡すノートの範囲 (strict, normal, all; 省略時は設定ファイル準拠)")
+	cmd.Flags().BoolVar(&flagEdit, "edit", false, "生成後に日記をエディタで開く")
+	cmd.Flags().BoolVar(&flagPush, "push", false, "生成後(--edit時はエディタを閉じた後)にgit commit & pushする")
 
 	return cmd
 }
@@ -68,59 +96,156 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	msg, err := messagesFor(cfg)
+	if err != nil {
+		return err
+	}
+
 	stdout := cmd.OutOrStdout()
 	stderr := cmd.ErrOrStderr()
 
-	result, err := diaryWorkflowRunner(cmd.Context(), cfg, flagProvider, stderr)
+	mode, err := resolveWriteMode(flagMode)
+	if err != nil {
+		return err
+	}
+	if (flagEdit || flagPush) && !isMarkdownOutput(flagOutput) {
+		return fmt.Errorf("--edit and --push require markdown output")
+	}
+	// Check the existing diary before any AI call is made for it.
+	if isMarkdownOutput(flagOutput) && (mode == writeModeSkip || mode == writeModeMerge) {
+		targetDate, err := resolveConfiguredDate(cfg)
+		if err != nil {
+			return err
+		}
+		if mode == writeModeMerge {
+			if err := checkMergeable(cfg, targetDate); err != nil {
+				return err
+			}
+		} else {
+			path, exists, err := existingDiaryPath(cfg, targetDate)
+			if err != nil {
+				return err
+			}
+			if exists {
+				if err := writeLine(stderr, fmt.Sprintf(msg.DiarySkipped, path)); err != nil {
+					return err
+				}
+				return editAndPushDiary(stdout, cfg, targetDate, path)
+			}
+		}
+	}
+
+	ctx := commandContext(cmd)
+	result, err := diaryWorkflowRunner(ctx, cfg, flagProvider, stderr, nil)
 	if err != nil {
 		return err
 	}
 
-	if err := handleRunOutput(stdout, stderr, cfg, result, flagOutput); err != nil {
+	if err := handleRunOutput(ctx, stdout, stderr, cfg, result, flagOutput, mode); err != nil {
 		return err
 	}
 
 	if flagDiscord {
-		if err := discordPoster(cfg, result); err != nil {
-			if writeErr := writeLine(stderr, fmt.Sprintf("Discord投稿に失敗しました: %v", err)); writeErr != nil {
+		if err := discordPoster(ctx, cfg, result); err != nil {
+			if writeErr := writeLine(stderr, fmt.Sprintf(msg.DiscordFailed, err)); writeErr != nil {
 				return writeErr
 			}
 		} else {
-			if err := writeLine(stderr, "Discordへ投稿しました"); err != nil {
+			if err := writeLine(stderr, msg.DiscordPosted); err != nil {
 				return err
 			}
 		}
 	}
 
+	if flagEdit || flagPush {
+		path, _, err := existingDiaryPath(cfg, result.TargetDate)
+		if err != nil {
+			return err
+		}
+		return editAndPushDiary(stdout, cfg, result.TargetDate, path)
+	}
+
 	return nil
 }
 
-func runDiaryWorkflow(ctx context.Context, cfg *config.Config, providerName string, progress io.Writer) (*diaryRunResult, error) {
-	loc, err := cfg.DiaryLocation()
+// editAndPushDiary opens the diary at path when --edit is set and then pushes it when --push is set.
+func editAndPushDiary(w io.Writer, cfg *config.Config, targetDate time.Time, path string) error {
+	if flagEdit {
+		if err := editorRunner(cfg.Diary.Editor, path); err != nil {
+			return err
+		}
+	}
+	if flagPush {
+		return diaryPusher(w, cfg, targetDate)
+	}
+	return nil
+}
+
+func runDiaryWorkflow(ctx context.Context, cfg *config.Config, providerName string, progress, stream io.Writer) (*diaryRunResult, error) {
+	targetDate, err := resolveConfiguredDate(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	targetDate, err := resolveDate(loc)
+	return runDiaryWorkflowForDate(ctx, cfg, targetDate, providerName, progress, stream)
+}
+
+// runDiaryWorkflowForDate fetches, preprocesses and summarizes the notes of
+// targetDate. When stream is non-nil, the summary is written to it as it is generated.
+func runDiaryWorkflowForDate(ctx context.Context, cfg *config.Config, targetDate time.Time, providerName string, progress, stream io.Writer) (*diaryRunResult, error) {
+	loc, err := cfg.DiaryLocation()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := messagesFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dayStartHour, err := cfg.DiaryDayStartHour()
+	if err != nil {
+		return nil, err
+	}
+	buckets, err := diaryTimeBuckets(cfg, msg, dayStartHour)
 	if err != nil {
 		return nil, err
 	}
 
-	startTime, endTime := resolveDiaryWindow(targetDate)
+	startTime, endTime := resolveDiaryWindow(targetDate, dayStartHour)
 	if progress != nil {
-		if err := writeLine(progress, fmt.Sprintf("%s の対象期間: %s 〜 %s", targetDate.Format("2006-01-02"), startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))); err != nil {
+		if err := writeLine(progress, fmt.Sprintf(msg.TargetWindow, targetDate.Format("2006-01-02"), startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))); err != nil {
 			return nil, err
 		}
 	}
 
-	notes, err := fetchNotesForWindow(cfg, startTime, endTime)
+	notes, err := loadNotesForWindow(ctx, cfg, startTime, endTime)
 	if err != nil {
 		return nil, err
 	}
-	notes = preprocess.EnrichNotesWithSummaly(filterNotes(notes), preprocess.NewSummalyClientWithEndpoint(cfg.Summaly.Endpoint))
+	privacy, err := privacyPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	filter := noteFilterFromConfig(cfg)
+	notes = privacy.Apply(attachRelatedNotes(ctx, cfg, filterNotes(notes, filter), filter))
+	if cfg.Diary.Attachments || cfg.Diary.Gallery || cfg.AI.Multimodal {
+		notes = attachDriveFiles(ctx, cfg, notes)
+	}
+	if cfg.Diary.Attachments {
+		notes = preprocess.EnrichNotesWithFiles(notes, attachmentLabels(msg))
+	}
+	summaly, err := summalyClientFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if summaly != nil {
+		notes = preprocess.EnrichNotesWithSummaly(ctx, notes, summaly)
+		if err := summaly.Cache.Save(); err != nil {
+			return nil, err
+		}
+	}
 
 	if progress != nil {
-		if err := writeLine(progress, fmt.Sprintf("Misskeyから%d件のノートを取得しました", len(notes))); err != nil {
+		if err := writeLine(progress, fmt.Sprintf(msg.FetchedNotes, len(notes))); err != nil {
 			return nil, err
 		}
 	}
@@ -130,36 +255,135 @@ func runDiaryWorkflow(ctx context.Context, cfg *config.Config, providerName stri
 			StartTime:  startTime,
 			EndTime:    endTime,
 			Title:      targetDate.Format("2006-01-02"),
-			Summary:    "この日は対象期間内のノートがありませんでした。",
+			Summary:    msg.NoNotesSummary,
 		}, nil
 	}
 
-	grouped := preprocess.GroupNotes(notes, loc)
-	formattedNotes := preprocess.FormatGroupedNotes(grouped, loc)
+	rest, channelGroups := preprocess.SplitByChannel(notes, resolveChannels(ctx, cfg, msg))
+	var sharedGroups []preprocess.TimeGroup
+	if cfg.Diary.IncludeRenotes {
+		rest, sharedGroups = preprocess.SplitShared(rest, msg.SharedSection)
+	}
+	grouped := append(preprocess.GroupNotesByBuckets(rest, loc, buckets), channelGroups...)
+	grouped = append(grouped, sharedGroups...)
+	cite := cfg.Diary.Sources && strings.TrimSpace(cfg.Misskey.InstanceURL) != ""
+	redactor, err := redactorFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	formatNotes := func(groups []preprocess.TimeGroup) string {
+		text := preprocess.FormatGroupedNotes(groups, loc)
+		if cite {
+			text = preprocess.FormatGroupedNotesWithCitations(groups, loc)
+		}
+		if redactor != nil {
+			text = redactor.Redact(text)
+		}
+		return text
+	}
+	formattedNotes := formatNotes(grouped)
 
-	providerName = resolveProviderName(cfg, providerName)
-	if providerName == "" {
+	providerNames := resolveProviderNames(cfg, providerName)
+	if len(providerNames) == 0 {
 		return nil, fmt.Errorf("ai.default_provider か --provider を指定してください")
 	}
 
+	prompts, err := loadPrompts(cfg)
+	if err != nil {
+		return nil, err
+	}
+	promptData := buildPromptData(cfg, targetDate, notes, grouped, formattedNotes)
+	promptData.CiteSources = cite
+
 	var summary string
-	provider, err := buildProviderFromConfig(ctx, providerName, cfg)
+	provider, err := buildProviderChain(ctx, providerNames, cfg, msg, progress)
 	if err != nil {
 		return nil, err
 	}
-	summary, err = ai.GenerateSummary(ctx, provider, formattedNotes, targetDate)
+	var images []ai.Image
+	if cfg.AI.Multimodal {
+		images = collectThumbnails(ctx, notes, cfg.AI.MaxImages)
+	}
+	var sources []generator.Source
+	if cite {
+		sources = generator.BuildSources(cfg.Misskey.InstanceURL, notes, loc)
+	}
+	finalize := func(text string) string {
+		if redactor != nil && cfg.Redaction.Restore {
+			text = redactor.Restore(text)
+		}
+		if cite {
+			text = generator.LinkCitations(text, sources)
+		}
+		return text
+	}
+	var streamed *lineRewriter
+	var summaryWriter io.Writer
+	if stream != nil {
+		streamed = &lineRewriter{w: stream}
+		if (redactor != nil && cfg.Redaction.Restore) || cite {
+			streamed.rewrite = finalize
+		}
+		summaryWriter = streamed
+	}
+
+	budget := ai.MaxInputTokens(provider, cfg.AI.MaxInputTokens)
+	tokens, err := ai.SummaryPromptTokens(provider, prompts, promptData)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case tokens > budget:
+		// Too long for one prompt: summarize each group and merge the parts.
+		fits := func(notes string) bool {
+			data := promptData
+			data.Notes = notes
+			n, err := ai.SummaryPromptTokens(provider, prompts, data)
+			return err == nil && n <= budget
+		}
+		chunks := summaryChunks(grouped, formatNotes, fits)
+		if progress != nil {
+			if err := writeLine(progress, fmt.Sprintf(msg.ChunkedSummary, tokens, len(chunks))); err != nil {
+				return nil, err
+			}
+		}
+		summary, err = ai.GenerateChunkedSummary(ctx, provider, prompts, promptData, chunks, budget, images, summaryWriter)
+	case summaryWriter != nil:
+		summary, err = ai.GenerateSummaryStream(ctx, provider, prompts, promptData, images, summaryWriter)
+	default:
+		summary, err = ai.GenerateSummaryWithImages(ctx, provider, prompts, promptData, images)
+	}
+	if err == nil && streamed != nil {
+		err = streamed.Flush()
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	titleProvider, err := buildProviderFromConfig(ctx, providerName, cfg)
+	generatedBy := usedProviderName(provider)
+	if len(providerNames) > 1 {
+		if err := writeLine(progress, fmt.Sprintf(msg.GeneratedBy, generatedBy)); err != nil {
+			return nil, err
+		}
+	}
+
+	titleProvider, err := buildProviderChain(ctx, providerNames, cfg, msg, progress)
 	if err != nil {
 		return nil, err
 	}
-	title, err := ai.GenerateTitle(ctx, titleProvider, summary, targetDate)
+	promptData.Summary = summary
+	if cite {
+		// Keep citations out of the title.
+		promptData.Summary = generator.LinkCitations(summary, nil)
+	}
+	title, err := ai.GenerateTitle(ctx, titleProvider, prompts, promptData)
 	if err != nil {
 		return nil, err
 	}
+	summary = finalize(summary)
+	if redactor != nil && cfg.Redaction.Restore {
+		title = redactor.Restore(title)
+	}
 
 	return &diaryRunResult{
 		TargetDate: targetDate,
@@ -168,38 +392,86 @@ func runDiaryWorkflow(ctx context.Context, cfg *config.Config, providerName stri
 		Title:      title,
 		Summary:    summary,
 		Notes:      notes,
+		Sources:    sources,
+		Provider:   generatedBy,
 	}, nil
 }
 
-func handleRunOutput(stdout, status io.Writer, cfg *config.Config, result *diaryRunResult, output string) error {
+// diaryTimeBuckets returns diary.time_buckets, or the default buckets rotated so
+// that the one containing the day start hour comes first.
+func diaryTimeBuckets(cfg *config.Config, msg *i18n.Messages, dayStartHour int) ([]preprocess.TimeBucket, error) {
+	if err := cfg.ValidateTimeBuckets(); err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Diary.TimeBuckets) > 0 {
+		buckets := make([]preprocess.TimeBucket, 0, len(cfg.Diary.TimeBuckets))
+		for _, b := range cfg.Diary.TimeBuckets {
+			buckets = append(buckets, preprocess.TimeBucket{Label: b.Label, Start: b.Start, End: b.End})
+		}
+		return buckets, nil
+	}
+
+	return preprocess.DefaultTimeBucketsFrom(msg, dayStartHour), nil
+}
+
+func loadPrompts(cfg *config.Config) (*ai.Prompts, error) {
+	msg, err := messagesFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := cfg.PromptsDir()
+	if err != nil {
+		return nil, err
+	}
+	return ai.LoadPrompts(dir, msg.Language)
+}
+
+func messagesFor(cfg *config.Config) (*i18n.Messages, error) {
+	return i18n.For(cfg.Diary.Language)
+}
+
+func buildPromptData(cfg *config.Config, targetDate time.Time, notes []models.Note, grouped []preprocess.TimeGroup, formattedNotes string) ai.PromptData {
+	groups := make([]ai.PromptTimeGroup, 0, len(grouped))
+	for _, g := range grouped {
+		groups = append(groups, ai.PromptTimeGroup{Label: g.Label, NoteCount: len(g.Notes)})
+	}
+
+	return ai.PromptData{
+		Date:       targetDate.Format("2006-01-02"),
+		Author:     cfg.Diary.Author,
+		NoteCount:  len(notes),
+		TimeGroups: groups,
+		Notes:      formattedNotes,
+	}
+}
+
+func handleRunOutput(ctx context.Context, stdout, status io.Writer, cfg *config.Config, result *diaryRunResult, output, mode string) error {
+	msg, err := messagesFor(cfg)
+	if err != nil {
+		return err
+	}
+
 	switch strings.ToLower(strings.TrimSpace(output)) {
 	case "", outputMarkdown:
-		if strings.TrimSpace(cfg.Diary.OutputDir) == "" {
-			return fmt.Errorf("diary.output_dir is required for markdown output")
-		}
-		fileTime := time.Date(
-			result.TargetDate.Year(),
-			result.TargetDate.Month(),
-			result.TargetDate.Day(),
-			result.StartTime.In(result.TargetDate.Location()).Hour(),
-			result.StartTime.In(result.TargetDate.Location()).Minute(),
-			0,
-			0,
-			result.TargetDate.Location(),
-		)
-		markdown := generator.BuildMarkdown(fileTime, cfg.Diary.Author, result.Title, result.Summary)
-		outputPath, err := saveDiary(cfg.Diary.OutputDir, result.TargetDate, markdown)
+		outputPath, err := writeMarkdownDiary(ctx, cfg, result, mode)
+		if errors.Is(err, errDiaryExists) {
+			if status != nil {
+				return writeLine(status, fmt.Sprintf(msg.DiarySkipped, outputPath))
+			}
+			return nil
+		}
 		if err != nil {
 			return err
 		}
 		if status != nil {
-			if err := writeLine(status, fmt.Sprintf("保存しました: %s", outputPath)); err != nil {
+			if err := writeLine(status, fmt.Sprintf(msg.Saved, outputPath)); err != nil {
 				return err
 			}
 		}
 		return nil
 	case outputSummary:
-		return writeLine(stdout, generator.BuildSummaryText(result.TargetDate, len(result.Notes), result.Title, result.Summary))
+		return writeLine(stdout, generator.BuildSummaryText(msg, result.TargetDate, len(result.Notes), result.Title, result.Summary))
 	case outputJSON:
 		payload := generator.BuildJSONOutput(
 			result.TargetDate,
@@ -209,6 +481,7 @@ func handleRunOutput(stdout, status io.Writer, cfg *config.Config, result *diary
 			result.Summary,
 			result.Notes,
 		)
+		payload.Provider = result.Provider
 		encoded, err := json.MarshalIndent(payload, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to encode json output: %w", err)
@@ -221,11 +494,153 @@ func handleRunOutput(stdout, status io.Writer, cfg *config.Config, result *diary
 	}
 }
 
-func resolveProviderName(cfg *config.Config, flagValue string) string {
-	if v := strings.ToLower(strings.TrimSpace(flagValue)); v != "" {
-		return v
+func isMarkdownOutput(output string) bool {
+	output = strings.ToLower(strings.TrimSpace(output))
+	return output == "" || output == outputMarkdown
+}
+
+func resolveWriteMode(mode string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(mode)); v {
+	case "":
+		return writeModeMerge, nil
+	case writeModeReplace, writeModeMerge, writeModeAppend, writeModeSkip:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unsupported mode: %s (expected replace, merge, append or skip)", mode)
+	}
+}
+
+// existingDiaryPath reports whether the diary for targetDate already exists.
+func existingDiaryPath(cfg *config.Config, targetDate time.Time) (string, bool, error) {
+	relPath, err := diaryRelPath(cfg, targetDate)
+	if err != nil {
+		return "", false, err
+	}
+
+	path := filepath.Join(cfg.Diary.OutputDir, relPath)
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return path, false, nil
+		}
+		return "", false, fmt.Errorf("failed to check existing diary: %w", err)
+	}
+	return path, true, nil
+}
+
+// checkMergeable fails when the diary for targetDate exists but has no
+// generated section to merge into.
+func checkMergeable(cfg *config.Config, targetDate time.Time) error {
+	path, exists, err := existingDiaryPath(cfg, targetDate)
+	if err != nil || !exists {
+		return err
+	}
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read existing diary: %w", err)
+	}
+	if !generator.HasGeneratedSection(string(existing)) {
+		return fmt.Errorf("failed to merge into %s (use --mode replace or append): existing diary: %w", path, generator.ErrNoGeneratedSection)
+	}
+	return nil
+}
+
+func writeMarkdownDiary(ctx context.Context, cfg *config.Config, result *diaryRunResult, mode string) (string, error) {
+	if strings.TrimSpace(cfg.Diary.OutputDir) == "" {
+		return "", fmt.Errorf("diary.output_dir is required for markdown output")
+	}
+	msg, err := messagesFor(cfg)
+	if err != nil {
+		return "", err
+	}
+	fileTime := time.Date(
+		result.TargetDate.Year(),
+		result.TargetDate.Month(),
+		result.TargetDate.Day(),
+		result.StartTime.In(result.TargetDate.Location()).Hour(),
+		result.StartTime.In(result.TargetDate.Location()).Minute(),
+		0,
+		0,
+		result.TargetDate.Location(),
+	)
+	relPath, err := diaryRelPath(cfg, result.TargetDate)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := generator.LoadMarkdownTemplate(cfg.Diary.Template)
+	if err != nil {
+		return "", err
+	}
+	data := generator.NewMarkdownData(msg, fileTime, cfg.Diary.Author, result.Title, result.Summary, len(result.Notes))
+	data.Sources = result.Sources
+	if cfg.Diary.Gallery {
+		// Don't download images for a diary that is about to be skipped.
+		if mode == writeModeSkip {
+			path, exists, err := existingDiaryPath(cfg, result.TargetDate)
+			if err != nil {
+				return "", err
+			}
+			if exists {
+				return path, errDiaryExists
+			}
+		}
+		data.Images, err = downloadGallery(ctx, cfg.Diary.OutputDir, relPath, result.Notes)
+		if err != nil {
+			return "", err
+		}
+	}
+	markdown, err := tmpl.Render(data)
+	if err != nil {
+		return "", err
+	}
+	return saveDiary(cfg.Diary.OutputDir, relPath, markdown, mode)
+}
+
+// resolveProviderNames returns the providers to try in order: the
+// comma-separated --provider flag, or ai.default_provider.
+func resolveProviderNames(cfg *config.Config, flagValue string) []string {
+	names := cfg.AI.DefaultProvider
+	if strings.TrimSpace(flagValue) != "" {
+		names = strings.Split(flagValue, ",")
+	}
+
+	var resolved []string
+	for _, name := range names {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved
+}
+
+// buildProviderChain builds the provider for names. More than one name yields
+// an ai.FallbackProvider that reports each failover to progress.
+func buildProviderChain(ctx context.Context, names []string, cfg *config.Config, msg *i18n.Messages, progress io.Writer) (ai.AIProvider, error) {
+	if len(names) == 1 {
+		return buildProviderFromConfig(ctx, names[0], cfg)
 	}
-	return strings.ToLower(strings.TrimSpace(cfg.AI.DefaultProvider))
+
+	providers := make([]ai.AIProvider, 0, len(names))
+	for _, name := range names {
+		provider, err := buildProviderFromConfig(ctx, name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	chain := ai.NewFallbackProvider(providers...)
+	chain.OnFailover = func(from, to string, err error) {
+		_ = writeLine(progress, fmt.Sprintf(msg.ProviderFailed, from, to, err))
+	}
+	return chain, nil
+}
+
+// usedProviderName returns the name of the provider that answered last.
+func usedProviderName(provider ai.AIProvider) string {
+	if chain, ok := provider.(*ai.FallbackProvider); ok {
+		return chain.Used()
+	}
+	return provider.Name()
 }
 
 func buildProviderFromConfig(ctx context.Context, name string, cfg *config.Config) (ai.AIProvider, error) {
@@ -245,12 +660,60 @@ func buildProviderFromConfig(ctx context.Context, name string, cfg *config.Confi
 			return nil, fmt.Errorf("ai.gemini.api_key is required")
 		}
 		return ai.NewGeminiProvider(ctx, cfg.AI.Gemini.APIKey, cfg.AI.Gemini.Model)
+	case "local":
+		if strings.TrimSpace(cfg.AI.Local.Model) == "" {
+			return nil, fmt.Errorf("ai.local.model is required")
+		}
+		return ai.NewLocalProvider(cfg.AI.Local.BaseURL, cfg.AI.Local.Model), nil
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", name)
 	}
 }
 
-func fetchNotesForWindow(cfg *config.Config, startTime, endTime time.Time) ([]models.Note, error) {
+func loadNotesForWindow(ctx context.Context, cfg *config.Config, startTime, endTime time.Time) ([]models.Note, error) {
+	if path := strings.TrimSpace(flagFromExport); path != "" {
+		notes, err := loadExportNotes(path)
+		if err != nil {
+			return nil, err
+		}
+		return misskey.NotesInRange(notes, startTime, endTime), nil
+	}
+	return fetchNotesForWindow(ctx, cfg, startTime, endTime)
+}
+
+// explainMisskeyError tells a rejected token apart from an instance that is
+// down, since only the former needs the user to act.
+func explainMisskeyError(err error) error {
+	switch {
+	case errors.Is(err, httpretry.ErrUnauthorized):
+		return fmt.Errorf("the token was rejected; check misskey.token: %w", err)
+	case errors.Is(err, httpretry.ErrUnavailable):
+		return fmt.Errorf("the instance is unavailable; try again later: %w", err)
+	}
+	return err
+}
+
+var exportCache struct {
+	path  string
+	notes []models.Note
+}
+
+// loadExportNotes parses the export once per path so backfill does not reread it for every day.
+func loadExportNotes(path string) ([]models.Note, error) {
+	if exportCache.path == path {
+		return exportCache.notes, nil
+	}
+
+	notes, err := misskey.LoadNotesExport(path)
+	if err != nil {
+		return nil, err
+	}
+	exportCache.path = path
+	exportCache.notes = notes
+	return notes, nil
+}
+
+func fetchNotesForWindow(ctx context.Context, cfg *config.Config, startTime, endTime time.Time) ([]models.Note, error) {
 	if strings.TrimSpace(cfg.Misskey.InstanceURL) == "" {
 		return nil, fmt.Errorf("misskey.instance_url is required")
 	}
@@ -258,31 +721,103 @@ func fetchNotesForWindow(cfg *config.Config, startTime, endTime time.Time) ([]mo
 		return nil, fmt.Errorf("misskey.token is required")
 	}
 
+	store, err := openNoteArchive(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var state *archive.State
+	if store != nil {
+		state, err = store.LoadState()
+		if err != nil {
+			return nil, err
+		}
+		if state.Covers(startTime, endTime) {
+			return store.Notes(startTime, endTime)
+		}
+	}
+
+	fetchedAt := time.Now()
 	client := misskey.NewClient(cfg.Misskey.InstanceURL, cfg.Misskey.Token)
-	me, err := client.GetMe()
+	me, err := client.GetMe(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user info: %w", err)
+		return nil, fmt.Errorf("failed to get user info: %w", explainMisskeyError(err))
 	}
 
-	notes, err := client.GetNotesForTimeRange(me.ID, startTime, endTime, true)
+	notes, err := client.GetNotesForTimeRange(ctx, me.ID, startTime, endTime, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch notes: %w", err)
+		return nil, fmt.Errorf("failed to fetch notes: %w", explainMisskeyError(err))
+	}
+
+	if store != nil {
+		coveredEnd := endTime
+		if fetchedAt.Before(coveredEnd) {
+			coveredEnd = fetchedAt
+		}
+		if err := archiveNotes(store, state, me.ID, notes, startTime, coveredEnd); err != nil {
+			return nil, err
+		}
 	}
 
 	return notes, nil
 }
 
-func filterNotes(notes []models.Note) []models.Note {
+// openNoteArchive returns the local note archive, or nil when archive.enabled is off.
+func openNoteArchive(cfg *config.Config) (*archive.Store, error) {
+	if !cfg.Archive.Enabled {
+		return nil, nil
+	}
+	dir, err := cfg.ArchiveDir()
+	if err != nil {
+		return nil, err
+	}
+	return archive.NewStore(dir), nil
+}
+
+func archiveNotes(store *archive.Store, state *archive.State, userID string, notes []models.Note, startTime, endTime time.Time) error {
+	if state.UserID != "" && state.UserID != userID {
+		return fmt.Errorf("note archive %s belongs to another user (%s)", store.Dir(), state.UserID)
+	}
+	if err := store.Put(notes); err != nil {
+		return fmt.Errorf("failed to archive notes: %w", err)
+	}
+	state.UserID = userID
+	state.AddCovered(startTime, endTime)
+	return store.SaveState(state)
+}
+
+// noteFilter selects which fetched notes go into the diary.
+type noteFilter struct {
+	IncludeReplies bool
+	IncludeRenotes bool
+	Channels       []string
+}
+
+func noteFilterFromConfig(cfg *config.Config) noteFilter {
+	return noteFilter{
+		IncludeReplies: cfg.Diary.IncludeReplies,
+		IncludeRenotes: cfg.Diary.IncludeRenotes,
+		Channels:       cfg.Diary.Channels,
+	}
+}
+
+// filterNotes drops pure renotes unless IncludeRenotes is set, replies unless
+// IncludeReplies is set, and channel notes outside the Channels list. Quotes
+// are kept, but without the quoted note unless IncludeRenotes is set, so other
+// users' text is only passed on when asked for.
+func filterNotes(notes []models.Note, filter noteFilter) []models.Note {
 	filtered := make([]models.Note, 0, len(notes))
 	for _, note := range notes {
-		if note.ReplyID != nil {
+		if note.ReplyID != nil && !filter.IncludeReplies {
 			continue
 		}
-		if note.ChannelID != nil {
+		if note.ChannelID != nil && !slices.Contains(filter.Channels, *note.ChannelID) {
 			continue
 		}
-		if !note.IsOriginalNote() {
-			continue
+		if !filter.IncludeRenotes {
+			if !note.IsOriginalNote() {
+				continue
+			}
+			note.Renote = nil
 		}
 		filtered = append(filtered, note)
 	}
@@ -293,23 +828,162 @@ func filterNotes(notes []models.Note) []models.Note {
 	return filtered
 }
 
-func postSummaryToDiscord(cfg *config.Config, result *diaryRunResult) error {
+// redactorFor returns a redactor for redaction.* when it is enabled, or nil.
+func redactorFor(cfg *config.Config) (*preprocess.Redactor, error) {
+	if !cfg.Redaction.Enabled {
+		return nil, nil
+	}
+	return preprocess.NewRedactor(cfg.Redaction.Words, cfg.Redaction.Patterns)
+}
+
+// privacyPolicy returns the policy for --privacy, or privacy.level when the
+// flag is not set, extended with privacy.exclude_visibilities and exclude_cw.
+func privacyPolicy(cfg *config.Config) (preprocess.PrivacyPolicy, error) {
+	level := cfg.Privacy.Level
+	if v := strings.TrimSpace(flagPrivacy); v != "" {
+		level = v
+	}
+
+	policy, err := preprocess.PrivacyPolicyForLevel(level)
+	if err != nil {
+		return preprocess.PrivacyPolicy{}, err
+	}
+	for _, v := range cfg.Privacy.ExcludeVisibilities {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v != "" && !slices.Contains(policy.ExcludeVisibilities, v) {
+			policy.ExcludeVisibilities = append(policy.ExcludeVisibilities, v)
+		}
+	}
+	policy.ExcludeCW = policy.ExcludeCW || cfg.Privacy.ExcludeCW
+	return policy, nil
+}
+
+// lookupClient returns the client used to fill in related notes, drive files
+// and channel names, or nil when those lookups must not reach the API: when
+// notes come from --from-export, which should work offline, or when Misskey
+// is not configured.
+func lookupClient(cfg *config.Config) *misskey.Client {
+	if strings.TrimSpace(flagFromExport) != "" {
+		return nil
+	}
+	if strings.TrimSpace(cfg.Misskey.InstanceURL) == "" || strings.TrimSpace(cfg.Misskey.Token) == "" {
+		return nil
+	}
+	return misskey.NewClient(cfg.Misskey.InstanceURL, cfg.Misskey.Token)
+}
+
+// resolveChannels looks up the names of diary.channels for their section
+// headings, falling back to the channel ID when a lookup fails.
+func resolveChannels(ctx context.Context, cfg *config.Config, msg *i18n.Messages) []preprocess.Channel {
+	if len(cfg.Diary.Channels) == 0 {
+		return nil
+	}
+
+	client := lookupClient(cfg)
+
+	channels := make([]preprocess.Channel, 0, len(cfg.Diary.Channels))
+	for _, id := range cfg.Diary.Channels {
+		name := id
+		if client != nil {
+			if channel, err := client.ShowChannel(ctx, id); err == nil && channel.Name != "" {
+				name = channel.Name
+			}
+		}
+		channels = append(channels, preprocess.Channel{ID: id, Label: fmt.Sprintf(msg.ChannelSection, name)})
+	}
+	return channels
+}
+
+// attachRelatedNotes fills in Note.Reply and Note.Renote, as enabled by filter,
+// when the fetched data only carries their IDs. Notes that cannot be fetched,
+// such as deleted ones, are left empty, as are all of them for --from-export.
+func attachRelatedNotes(ctx context.Context, cfg *config.Config, notes []models.Note, filter noteFilter) []models.Note {
+	if !filter.IncludeReplies && !filter.IncludeRenotes {
+		return notes
+	}
+	client := lookupClient(cfg)
+	if client == nil {
+		return notes
+	}
+
+	byID := make(map[string]*models.Note, len(notes))
+	for i := range notes {
+		byID[notes[i].ID] = &notes[i]
+	}
+
+	lookup := func(id string) *models.Note {
+		if note, ok := byID[id]; ok {
+			return note
+		}
+		note, err := client.ShowNote(ctx, id)
+		if err != nil {
+			note = nil
+		}
+		byID[id] = note
+		return note
+	}
+
+	for i := range notes {
+		if filter.IncludeReplies && notes[i].ReplyID != nil && notes[i].Reply == nil {
+			notes[i].Reply = lookup(*notes[i].ReplyID)
+		}
+		if filter.IncludeRenotes && notes[i].RenoteID != nil && notes[i].Renote == nil {
+			notes[i].Renote = lookup(*notes[i].RenoteID)
+		}
+	}
+	return notes
+}
+
+func postSummaryToDiscord(ctx context.Context, cfg *config.Config, result *diaryRunResult) error {
 	if strings.TrimSpace(cfg.Discord.WebhookURL) == "" {
 		return fmt.Errorf("discord.webhook_url is required when --discord is set")
 	}
 
-	client := discord.NewClient(cfg.Discord.WebhookURL)
-	return client.PostSummary(result.TargetDate.Format("2006-01-02"), len(result.Notes), result.Title, result.Summary)
+	msg, err := messagesFor(cfg)
+	if err != nil {
+		return err
+	}
+
+	links := make([]discord.Link, 0, len(result.Sources))
+	for _, s := range result.Sources {
+		links = append(links, discord.Link{Label: s.Time, URL: s.URL})
+	}
+
+	client := discord.NewClient(cfg.Discord.WebhookURL, msg)
+	return client.PostSummary(ctx, result.TargetDate.Format("2006-01-02"), len(result.Notes), result.Title, result.Summary, links...)
+}
+
+// diaryRelPath returns the diary file path for date relative to diary.output_dir.
+func diaryRelPath(cfg *config.Config, date time.Time) (string, error) {
+	return generator.DiaryPath(cfg.Diary.PathPattern, date)
 }
 
-func saveDiary(outputDir string, date time.Time, content string) (string, error) {
-	yearDir := filepath.Join(outputDir, date.Format("2006"))
-	if err := os.MkdirAll(yearDir, 0o755); err != nil {
+// saveDiary writes content to relPath under outputDir. When the file already
+// exists, mode decides whether it is replaced, merged into, appended to or left alone.
+func saveDiary(outputDir, relPath, content, mode string) (string, error) {
+	outputPath := filepath.Join(outputDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
 		return "", fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	filename := date.Format("0102") + ".md"
-	outputPath := filepath.Join(yearDir, filename)
+	if mode != writeModeReplace {
+		existing, err := os.ReadFile(outputPath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return "", fmt.Errorf("failed to read existing diary: %w", err)
+		case mode == writeModeSkip:
+			return outputPath, errDiaryExists
+		case mode == writeModeAppend:
+			content = generator.AppendGenerated(string(existing), content)
+		default:
+			merged, err := generator.MergeGenerated(string(existing), content)
+			if err != nil {
+				return "", fmt.Errorf("failed to merge into %s (use --mode replace or append): %w", outputPath, err)
+			}
+			content = merged
+		}
+	}
 
 	if err := os.WriteFile(outputPath, []byte(content), 0o644); err != nil {
 		return "", fmt.Errorf("failed to write file: %w", err)
@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/soli0222/diary-cli/internal/config"
+	"github.com/soli0222/diary-cli/internal/generator"
 	"github.com/soli0222/diary-cli/internal/models"
 )
 
@@ -50,7 +53,7 @@ func TestFilterNotes(t *testing.T) {
 		},
 	}
 
-	got := filterNotes(notes)
+	got := filterNotes(notes, noteFilter{})
 
 	if len(got) != 2 {
 		t.Fatalf("len(filterNotes()) = %d, want 2", len(got))
@@ -58,17 +61,91 @@ func TestFilterNotes(t *testing.T) {
 	if got[0].ID != "keep-early" || got[1].ID != "keep-late" {
 		t.Fatalf("unexpected order: %#v", got)
 	}
+
+	got = filterNotes(notes, noteFilter{IncludeReplies: true})
+	if len(got) != 3 || got[1].ID != "reply" {
+		t.Fatalf("filterNotes(includeReplies) = %#v, want reply kept", got)
+	}
+
+	got = filterNotes(notes, noteFilter{IncludeRenotes: true})
+	if len(got) != 3 || got[1].ID != "renote" {
+		t.Fatalf("filterNotes(includeRenotes) = %#v, want renote kept", got)
+	}
+
+	got = filterNotes(notes, noteFilter{Channels: []string{"c1"}})
+	if len(got) != 3 || got[1].ID != "channel" {
+		t.Fatalf("filterNotes(channels) = %#v, want channel note kept", got)
+	}
 }
 
-func TestResolveProviderName(t *testing.T) {
+func TestPrivacyPolicyFlagOverridesConfig(t *testing.T) {
+	originalFlagPrivacy := flagPrivacy
+	defer func() { flagPrivacy = originalFlagPrivacy }()
+
 	cfg := &config.Config{}
-	cfg.AI.DefaultProvider = "Gemini"
+	cfg.Privacy.Level = "all"
+	cfg.Privacy.ExcludeVisibilities = []string{"Home"}
+
+	flagPrivacy = ""
+	policy, err := privacyPolicy(cfg)
+	if err != nil {
+		t.Fatalf("privacyPolicy() error = %v", err)
+	}
+	if policy.Allows(&models.Note{Visibility: "home"}) || !policy.Allows(&models.Note{Visibility: "specified"}) {
+		t.Fatalf("policy = %#v, want only home excluded", policy)
+	}
 
-	if got := resolveProviderName(cfg, " OpenAI "); got != "openai" {
-		t.Fatalf("resolveProviderName(flag) = %q", got)
+	flagPrivacy = "strict"
+	policy, err = privacyPolicy(cfg)
+	if err != nil {
+		t.Fatalf("privacyPolicy() error = %v", err)
+	}
+	if policy.Allows(&models.Note{Visibility: "specified"}) || policy.Allows(&models.Note{Visibility: "home"}) || !policy.ExcludeCW {
+		t.Fatalf("policy = %#v, want strict plus home", policy)
 	}
-	if got := resolveProviderName(cfg, ""); got != "gemini" {
-		t.Fatalf("resolveProviderName(config) = %q", got)
+
+	flagPrivacy = "secret"
+	if _, err := privacyPolicy(cfg); err == nil {
+		t.Fatal("expected error for unknown privacy level, got nil")
+	}
+}
+
+func TestLoadNotesForWindowFromExport(t *testing.T) {
+	originalFromExport := flagFromExport
+	defer func() { flagFromExport = originalFromExport }()
+
+	path := filepath.Join(t.TempDir(), "notes.json")
+	export := `[
+  {"id":"in","text":"in window","createdAt":"2026-02-22T21:00:00.000Z","replyId":null,"renoteId":null,"cw":null,"visibility":"public"},
+  {"id":"out","text":"next day","createdAt":"2026-02-23T21:00:00.000Z","replyId":null,"renoteId":null,"cw":null,"visibility":"public"}
+]`
+	if err := os.WriteFile(path, []byte(export), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	flagFromExport = path
+
+	start := time.Date(2026, 2, 22, 20, 0, 0, 0, time.UTC)
+	notes, err := loadNotesForWindow(context.Background(), &config.Config{}, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("loadNotesForWindow() error = %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != "in" {
+		t.Fatalf("unexpected notes: %#v", notes)
+	}
+}
+
+func TestResolveProviderNames(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.AI.DefaultProvider = []string{"Gemini", " claude "}
+
+	if got := resolveProviderNames(cfg, " OpenAI "); len(got) != 1 || got[0] != "openai" {
+		t.Fatalf("resolveProviderNames(flag) = %q", got)
+	}
+	if got := resolveProviderNames(cfg, "local, openai"); len(got) != 2 || got[0] != "local" || got[1] != "openai" {
+		t.Fatalf("resolveProviderNames(flag list) = %q", got)
+	}
+	if got := resolveProviderNames(cfg, ""); len(got) != 2 || got[0] != "gemini" || got[1] != "claude" {
+		t.Fatalf("resolveProviderNames(config) = %q", got)
 	}
 }
 
@@ -86,6 +163,7 @@ func TestBuildProviderFromConfigRequiresAPIKey(t *testing.T) {
 		{name: "claude", provider: "claude", wantErr: "ai.claude.api_key is required"},
 		{name: "openai", provider: "openai", wantErr: "ai.openai.api_key is required"},
 		{name: "gemini", provider: "gemini", wantErr: "ai.gemini.api_key is required"},
+		{name: "local", provider: "local", wantErr: "ai.local.model is required"},
 	}
 
 	for _, tt := range tests {
@@ -111,7 +189,7 @@ func TestHandleRunOutputRejectsUnsupportedOutput(t *testing.T) {
 		Summary:    "summary",
 	}
 
-	err := handleRunOutput(&bytes.Buffer{}, &bytes.Buffer{}, cfg, result, "yaml")
+	err := handleRunOutput(context.Background(), &bytes.Buffer{}, &bytes.Buffer{}, cfg, result, "yaml", writeModeMerge)
 	if err == nil || !strings.Contains(err.Error(), "unsupported output format: yaml") {
 		t.Fatalf("err = %v", err)
 	}
@@ -134,7 +212,7 @@ func TestHandleRunOutputMarkdownWritesStatusOnly(t *testing.T) {
 	}
 
 	var stdout, status bytes.Buffer
-	if err := handleRunOutput(&stdout, &status, cfg, result, outputMarkdown); err != nil {
+	if err := handleRunOutput(context.Background(), &stdout, &status, cfg, result, outputMarkdown, writeModeMerge); err != nil {
 		t.Fatalf("handleRunOutput() error = %v", err)
 	}
 
@@ -146,6 +224,70 @@ func TestHandleRunOutputMarkdownWritesStatusOnly(t *testing.T) {
 	}
 }
 
+func TestSaveDiaryModes(t *testing.T) {
+	t.Parallel()
+
+	generated := "---\ntitle: t\n---\n\n<!-- diary-cli:begin -->\nnew\n<!-- diary-cli:end -->\n"
+	existing := "---\ntitle: t\n---\n\n<!-- diary-cli:begin -->\nold\n<!-- diary-cli:end -->\n\nmanual note\n"
+
+	tests := []struct {
+		mode    string
+		want    string
+		wantErr error
+	}{
+		{mode: writeModeReplace, want: generated},
+		{mode: writeModeMerge, want: "---\ntitle: t\n---\n\n<!-- diary-cli:begin -->\nnew\n<!-- diary-cli:end -->\n\nmanual note\n"},
+		{mode: writeModeAppend, want: existing + "\nnew\n"},
+		{mode: writeModeSkip, want: existing, wantErr: errDiaryExists},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		relPath := filepath.Join("2026", "0404.md")
+		if err := os.MkdirAll(filepath.Join(dir, "2026"), 0o755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, relPath), []byte(existing), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+
+		path, err := saveDiary(dir, relPath, generated, tt.mode)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: saveDiary() error = %v, want %v", tt.mode, err, tt.wantErr)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: ReadFile() error = %v", tt.mode, err)
+		}
+		if string(content) != tt.want {
+			t.Fatalf("%s: content = %q, want %q", tt.mode, content, tt.want)
+		}
+	}
+
+	dir := t.TempDir()
+	relPath := "0404.md"
+	if err := os.WriteFile(filepath.Join(dir, relPath), []byte("handwritten\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := saveDiary(dir, relPath, generated, writeModeMerge); err == nil {
+		t.Fatal("expected merge into a diary without markers to fail, got nil")
+	}
+}
+
+func TestResolveWriteMode(t *testing.T) {
+	t.Parallel()
+
+	if got, err := resolveWriteMode(""); err != nil || got != writeModeMerge {
+		t.Fatalf("resolveWriteMode(\"\") = %q, %v, want merge", got, err)
+	}
+	if got, err := resolveWriteMode(" Append "); err != nil || got != writeModeAppend {
+		t.Fatalf("resolveWriteMode(\" Append \") = %q, %v, want append", got, err)
+	}
+	if _, err := resolveWriteMode("overwrite"); err == nil {
+		t.Fatal("expected error for unknown mode, got nil")
+	}
+}
+
 func TestRunRunKeepsSummaryOnDiscordFailure(t *testing.T) {
 	originalLoadConfig := loadConfig
 	originalWorkflowRunner := diaryWorkflowRunner
@@ -165,7 +307,7 @@ func TestRunRunKeepsSummaryOnDiscordFailure(t *testing.T) {
 	loadConfig = func() (*config.Config, error) {
 		return &config.Config{}, nil
 	}
-	diaryWorkflowRunner = func(ctx context.Context, cfg *config.Config, providerName string, progress io.Writer) (*diaryRunResult, error) {
+	diaryWorkflowRunner = func(ctx context.Context, cfg *config.Config, providerName string, progress, stream io.Writer) (*diaryRunResult, error) {
 		if err := writeLine(progress, "progress"); err != nil {
 			return nil, err
 		}
@@ -176,7 +318,7 @@ func TestRunRunKeepsSummaryOnDiscordFailure(t *testing.T) {
 			Notes:      []models.Note{{ID: "1"}},
 		}, nil
 	}
-	discordPoster = func(cfg *config.Config, result *diaryRunResult) error {
+	discordPoster = func(ctx context.Context, cfg *config.Config, result *diaryRunResult) error {
 		return errors.New("boom")
 	}
 	flagOutput = outputSummary
@@ -220,7 +362,7 @@ func TestRunSummaryKeepsOutputOnDiscordFailure(t *testing.T) {
 	loadConfig = func() (*config.Config, error) {
 		return &config.Config{}, nil
 	}
-	diaryWorkflowRunner = func(ctx context.Context, cfg *config.Config, providerName string, progress io.Writer) (*diaryRunResult, error) {
+	diaryWorkflowRunner = func(ctx context.Context, cfg *config.Config, providerName string, progress, stream io.Writer) (*diaryRunResult, error) {
 		if err := writeLine(progress, "summary progress"); err != nil {
 			return nil, err
 		}
@@ -231,7 +373,7 @@ func TestRunSummaryKeepsOutputOnDiscordFailure(t *testing.T) {
 			Notes:      []models.Note{{ID: "1"}, {ID: "2"}},
 		}, nil
 	}
-	discordPoster = func(cfg *config.Config, result *diaryRunResult) error {
+	discordPoster = func(ctx context.Context, cfg *config.Config, result *diaryRunResult) error {
 		return errors.New("boom")
 	}
 	summaryFlagDiscord = true
@@ -256,3 +398,85 @@ func TestRunSummaryKeepsOutputOnDiscordFailure(t *testing.T) {
 		t.Fatalf("stderr = %q, want discord warning", stderr.String())
 	}
 }
+
+func TestLookupClientSkipsExports(t *testing.T) {
+	originalFromExport := flagFromExport
+	defer func() { flagFromExport = originalFromExport }()
+
+	cfg := &config.Config{}
+	cfg.Misskey.InstanceURL = "https://misskey.example"
+	cfg.Misskey.Token = "token"
+
+	flagFromExport = ""
+	if lookupClient(cfg) == nil {
+		t.Fatal("lookupClient() = nil, want client")
+	}
+	flagFromExport = "notes.json"
+	if lookupClient(cfg) != nil {
+		t.Fatal("lookupClient() with --from-export = client, want nil")
+	}
+}
+
+func TestRunRunChecksMergeBeforeWorkflow(t *testing.T) {
+	originalLoadConfig := loadConfig
+	originalWorkflowRunner := diaryWorkflowRunner
+	originalFlagOutput, originalFlagMode, originalFlagDate := flagOutput, flagMode, flagDate
+	defer func() {
+		loadConfig = originalLoadConfig
+		diaryWorkflowRunner = originalWorkflowRunner
+		flagOutput, flagMode, flagDate = originalFlagOutput, originalFlagMode, originalFlagDate
+	}()
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "2026", "0404.md")
+	if err := os.MkdirAll(filepath.Dir(existing), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(existing, []byte("handwritten"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loadConfig = func() (*config.Config, error) {
+		cfg := &config.Config{}
+		cfg.Diary.OutputDir = dir
+		cfg.Diary.Timezone = "UTC"
+		return cfg, nil
+	}
+	diaryWorkflowRunner = func(ctx context.Context, cfg *config.Config, providerName string, progress, stream io.Writer) (*diaryRunResult, error) {
+		t.Fatal("workflow should not run when the diary cannot be merged")
+		return nil, nil
+	}
+	flagOutput, flagMode, flagDate = outputMarkdown, writeModeMerge, "2026-04-04"
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := runRun(cmd, nil)
+	if !errors.Is(err, generator.ErrNoGeneratedSection) || !strings.Contains(err.Error(), "--mode replace") {
+		t.Fatalf("runRun() error = %v", err)
+	}
+}
+
+func TestFilterNotesDropsQuotedNoteUnlessRenotesIncluded(t *testing.T) {
+	text, quoted := "my comment", "someone else's note"
+	notes := []models.Note{{
+		ID:       "quote",
+		Text:     &text,
+		RenoteID: &quoted,
+		Renote:   &models.Note{ID: "original", Text: &quoted},
+	}}
+
+	got := filterNotes(notes, noteFilter{})
+	if len(got) != 1 || got[0].Renote != nil {
+		t.Fatalf("filterNotes() = %#v, want quote without the quoted note", got)
+	}
+	if notes[0].Renote == nil {
+		t.Fatal("filterNotes() modified its input")
+	}
+
+	got = filterNotes(notes, noteFilter{IncludeRenotes: true})
+	if len(got) != 1 || got[0].Renote == nil {
+		t.Fatalf("filterNotes(IncludeRenotes) = %#v, want quoted note kept", got)
+	}
+}
@@ -3,6 +3,9 @@ package cli
 import (
 	"testing"
 	"time"
+
+	"github.com/soli0222/diary-cli/internal/config"
+	"github.com/soli0222/diary-cli/internal/preprocess"
 )
 
 func TestResolveDiaryWindow(t *testing.T) {
@@ -11,7 +14,7 @@ func TestResolveDiaryWindow(t *testing.T) {
 	loc := time.FixedZone("JST", 9*60*60)
 	targetDate := time.Date(2026, 2, 22, 0, 0, 0, 0, loc)
 
-	start, end := resolveDiaryWindow(targetDate)
+	start, end := resolveDiaryWindow(targetDate, 5)
 
 	wantStart := time.Date(2026, 2, 22, 5, 0, 0, 0, loc)
 	wantEnd := time.Date(2026, 2, 23, 5, 0, 0, 0, loc)
@@ -23,3 +26,66 @@ func TestResolveDiaryWindow(t *testing.T) {
 		t.Fatalf("end = %v, want %v", end, wantEnd)
 	}
 }
+
+func TestResolveDiaryWindowCustomStartHour(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("JST", 9*60*60)
+	targetDate := time.Date(2026, 2, 22, 0, 0, 0, 0, loc)
+
+	start, end := resolveDiaryWindow(targetDate, 18)
+
+	if want := time.Date(2026, 2, 22, 18, 0, 0, 0, loc); !start.Equal(want) {
+		t.Fatalf("start = %v, want %v", start, want)
+	}
+	if want := time.Date(2026, 2, 23, 18, 0, 0, 0, loc); !end.Equal(want) {
+		t.Fatalf("end = %v, want %v", end, want)
+	}
+}
+
+func TestDiaryTimeBuckets(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+
+	got, err := diaryTimeBuckets(cfg, nil, 18)
+	if err != nil {
+		t.Fatalf("diaryTimeBuckets() error = %v", err)
+	}
+	wantLabels := []string{
+		"夕方 (18:00-21:00)",
+		"夜 (21:00-5:00)",
+		"早朝 (5:00-9:00)",
+		"午前 (9:00-12:00)",
+		"午後 (12:00-17:00)",
+		"夕方 (17:00-18:00)",
+	}
+	if len(got) != len(wantLabels) {
+		t.Fatalf("default buckets should be split at the day start hour: %#v", got)
+	}
+	for i, want := range wantLabels {
+		if got[i].Label != want {
+			t.Fatalf("buckets[%d] = %q, want %q", i, got[i].Label, want)
+		}
+	}
+
+	got, err = diaryTimeBuckets(cfg, nil, 21)
+	if err != nil {
+		t.Fatalf("diaryTimeBuckets() error = %v", err)
+	}
+	if len(got) != 5 || got[0].Label != "夜 (21:00-5:00)" || got[4].Label != "夕方 (17:00-21:00)" {
+		t.Fatalf("default buckets should start at the day start hour: %#v", got)
+	}
+
+	cfg.Diary.TimeBuckets = []config.TimeBucketConfig{
+		{Label: "勤務中", Start: 18, End: 6},
+		{Label: "帰宅後", Start: 6, End: 18},
+	}
+	got, err = diaryTimeBuckets(cfg, nil, 18)
+	if err != nil {
+		t.Fatalf("diaryTimeBuckets() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != (preprocess.TimeBucket{Label: "勤務中", Start: 18, End: 6}) {
+		t.Fatalf("configured buckets = %#v", got)
+	}
+}
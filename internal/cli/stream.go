@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// lineRewriter passes streamed text to w a line at a time, so rewrite sees
+// whole redaction placeholders and citations. Without rewrite, text is passed
+// through as it arrives.
+type lineRewriter struct {
+	w       io.Writer
+	rewrite func(string) string
+	buf     []byte
+}
+
+func (l *lineRewriter) Write(p []byte) (int, error) {
+	if l.rewrite == nil {
+		return l.w.Write(p)
+	}
+	l.buf = append(l.buf, p...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(l.buf[:i+1])
+		l.buf = l.buf[i+1:]
+		if _, err := io.WriteString(l.w, l.rewrite(line)); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Flush writes any trailing partial line.
+func (l *lineRewriter) Flush() error {
+	if len(l.buf) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(l.w, l.rewrite(string(l.buf)))
+	l.buf = nil
+	return err
+}
+
+// countingWriter records how many bytes were written through it.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
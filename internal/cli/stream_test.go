@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineRewriter(t *testing.T) {
+	var out strings.Builder
+	w := &lineRewriter{w: &out, rewrite: func(s string) string {
+		return strings.ReplaceAll(s, "[EMAIL_1]", "a@example.com")
+	}}
+
+	for _, chunk := range []string{"連絡先は [EMA", "IL_1] です\n次", "の行 [EMAIL_1]"} {
+		if _, err := io.WriteString(w, chunk); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if got := out.String(); got != "連絡先は a@example.com です\n" {
+		t.Fatalf("before Flush() = %q", got)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := out.String(); got != "連絡先は a@example.com です\n次の行 a@example.com" {
+		t.Fatalf("after Flush() = %q", got)
+	}
+}
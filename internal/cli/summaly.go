@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"github.com/soli0222/diary-cli/internal/config"
+	"github.com/soli0222/diary-cli/internal/preprocess"
+)
+
+// summalyClientFor builds the Summaly client described by the summaly
+// section, or returns nil when no endpoint is configured.
+func summalyClientFor(cfg *config.Config) (*preprocess.SummalyClient, error) {
+	client := preprocess.NewSummalyClientWithEndpoint(cfg.Summaly.Endpoint)
+	if client == nil {
+		return nil, nil
+	}
+
+	if cfg.Summaly.Concurrency > 0 {
+		client.Concurrency = cfg.Summaly.Concurrency
+	}
+	client.Domains = preprocess.DomainFilter{
+		Allow: cfg.Summaly.AllowDomains,
+		Deny:  cfg.Summaly.DenyDomains,
+	}
+	if cfg.Summaly.CacheTTL > 0 {
+		path, err := cfg.SummalyCacheFile()
+		if err != nil {
+			return nil, err
+		}
+		client.Cache, err = preprocess.LoadSummalyCache(path, cfg.Summaly.CacheTTL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
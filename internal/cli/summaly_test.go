@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/config"
+)
+
+func TestSummalyClientFor(t *testing.T) {
+	cfg := &config.Config{}
+	if client, err := summalyClientFor(cfg); err != nil || client != nil {
+		t.Fatalf("summalyClientFor(no endpoint) = %v, %v", client, err)
+	}
+
+	cfg.Summaly.Endpoint = "https://summaly.example"
+	cfg.Summaly.Concurrency = 8
+	cfg.Summaly.DenyDomains = []string{"spotify.com", "youtube.com"}
+	cfg.Summaly.CacheTTL = time.Hour
+	cfg.Summaly.CacheFile = filepath.Join(t.TempDir(), "summaly.json")
+
+	client, err := summalyClientFor(cfg)
+	if err != nil {
+		t.Fatalf("summalyClientFor() error = %v", err)
+	}
+	if client.Concurrency != 8 || client.Cache == nil {
+		t.Fatalf("client = %+v", client)
+	}
+	if client.Domains.Allows("https://www.youtube.com/watch?v=1") || !client.Domains.Allows("https://example.com/") {
+		t.Fatalf("Domains = %+v", client.Domains)
+	}
+
+	cfg.Summaly.CacheTTL = 0
+	if client, err := summalyClientFor(cfg); err != nil || client.Cache != nil {
+		t.Fatalf("summalyClientFor(no cache) = %+v, %v", client, err)
+	}
+}
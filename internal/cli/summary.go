@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
 
@@ -21,7 +22,8 @@ func newSummaryCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&summaryFlagDiscord, "discord", false, "Discord Webhookにも投稿する")
-	cmd.Flags().StringVarP(&summaryFlagProvider, "provider", "p", "", "AIプロバイダ (claude, openai, gemini)")
+	cmd.Flags().StringVarP(&summaryFlagProvider, "provider", "p", "", "AIプロバイダ (claude, openai, gemini, local。カンマ区切りで順にフォールバック)")
+	cmd.Flags().StringVar(&flagPrivacy, "privacy", "", "AIと出力に渡すノートの範囲 (strict, normal, all; 省略時は設定ファイル準拠)")
 
 	return cmd
 }
@@ -32,25 +34,44 @@ func runSummary(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	msg, err := messagesFor(cfg)
+	if err != nil {
+		return err
+	}
+
 	stdout := cmd.OutOrStdout()
 	stderr := cmd.ErrOrStderr()
 
-	result, err := diaryWorkflowRunner(cmd.Context(), cfg, summaryFlagProvider, stderr)
+	// On a terminal, print the summary as it is generated and the header
+	// once the title is known. Piped output keeps the usual layout.
+	var stream *countingWriter
+	var streamWriter io.Writer
+	if isTerminal(stdout) {
+		stream = &countingWriter{w: stdout}
+		streamWriter = stream
+	}
+
+	ctx := commandContext(cmd)
+	result, err := diaryWorkflowRunner(ctx, cfg, summaryFlagProvider, stderr, streamWriter)
 	if err != nil {
 		return err
 	}
 
-	if err := writeLine(stdout, generator.BuildSummaryText(result.TargetDate, len(result.Notes), result.Title, result.Summary)); err != nil {
+	text := generator.BuildSummaryText(msg, result.TargetDate, len(result.Notes), result.Title, result.Summary)
+	if stream != nil && stream.n > 0 {
+		text = "\n\n" + generator.BuildSummaryHeader(msg, result.TargetDate, len(result.Notes), result.Title)
+	}
+	if err := writeLine(stdout, text); err != nil {
 		return err
 	}
 
 	if summaryFlagDiscord {
-		if err := discordPoster(cfg, result); err != nil {
-			if writeErr := writeLine(stderr, fmt.Sprintf("Discord投稿に失敗しました: %v", err)); writeErr != nil {
+		if err := discordPoster(ctx, cfg, result); err != nil {
+			if writeErr := writeLine(stderr, fmt.Sprintf(msg.DiscordFailed, err)); writeErr != nil {
 				return writeErr
 			}
 		} else {
-			if err := writeLine(stderr, "Discordへ投稿しました"); err != nil {
+			if err := writeLine(stderr, msg.DiscordPosted); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/soli0222/diary-cli/internal/archive"
+	"github.com/soli0222/diary-cli/internal/misskey"
+)
+
+const syncPageSize = 100
+
+var syncFlagSince string
+
+func newSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Misskeyノートをローカルアーカイブへ差分同期する",
+		RunE:  runSync,
+	}
+
+	cmd.Flags().StringVar(&syncFlagSince, "since", "", "この日付から同期し直す (YYYY-MM-DD, 初回は必須)")
+
+	return cmd
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(cfg.Misskey.InstanceURL) == "" {
+		return fmt.Errorf("misskey.instance_url is required")
+	}
+	if strings.TrimSpace(cfg.Misskey.Token) == "" {
+		return fmt.Errorf("misskey.token is required")
+	}
+
+	loc, err := cfg.DiaryLocation()
+	if err != nil {
+		return err
+	}
+	msg, err := messagesFor(cfg)
+	if err != nil {
+		return err
+	}
+
+	dir, err := cfg.ArchiveDir()
+	if err != nil {
+		return err
+	}
+	store := archive.NewStore(dir)
+	state, err := store.LoadState()
+	if err != nil {
+		return err
+	}
+
+	if syncFlagSince != "" {
+		since, err := time.ParseInLocation("2006-01-02", syncFlagSince, loc)
+		if err != nil {
+			return fmt.Errorf("invalid --since (expected YYYY-MM-DD): %w", err)
+		}
+		state.LastID = ""
+		state.SyncedUntil = since
+	} else if state.SyncedUntil.IsZero() {
+		return fmt.Errorf("初回の同期では --since を指定してください")
+	}
+
+	ctx := commandContext(cmd)
+	client := misskey.NewClient(cfg.Misskey.InstanceURL, cfg.Misskey.Token)
+	me, err := client.GetMe(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get user info: %w", explainMisskeyError(err))
+	}
+	if state.UserID != "" && state.UserID != me.ID {
+		return fmt.Errorf("note archive %s belongs to another user (%s)", store.Dir(), state.UserID)
+	}
+	state.UserID = me.ID
+
+	syncStart := time.Now()
+	from := state.SyncedUntil
+	total := 0
+
+	for {
+		req := misskey.GetUserNotesRequest{
+			UserID:           me.ID,
+			WithReplies:      true,
+			WithRenotes:      true,
+			WithChannelNotes: true,
+			Limit:            syncPageSize,
+		}
+		if state.LastID != "" {
+			req.SinceID = state.LastID
+		} else {
+			sinceMs := from.UnixMilli()
+			req.SinceDate = &sinceMs
+		}
+
+		notes, err := client.GetUserNotes(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to fetch notes: %w", explainMisskeyError(err))
+		}
+		if len(notes) == 0 {
+			break
+		}
+
+		if err := store.Put(notes); err != nil {
+			return fmt.Errorf("failed to archive notes: %w", err)
+		}
+		newest := notes[0]
+		for _, note := range notes[1:] {
+			if note.CreatedAt.After(newest.CreatedAt) {
+				newest = note
+			}
+		}
+		state.LastID = newest.ID
+		if err := store.SaveState(state); err != nil {
+			return err
+		}
+
+		total += len(notes)
+		if len(notes) < syncPageSize {
+			break
+		}
+	}
+
+	state.AddCovered(from, syncStart)
+	state.SyncedUntil = syncStart
+	if err := store.SaveState(state); err != nil {
+		return err
+	}
+
+	return writeLine(cmd.ErrOrStderr(), fmt.Sprintf(msg.SyncDone, total, store.Dir()))
+}
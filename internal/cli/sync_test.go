@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/soli0222/diary-cli/internal/config"
+	"github.com/soli0222/diary-cli/internal/misskey"
+)
+
+func TestRunSyncThenFetchReadsFromArchive(t *testing.T) {
+	originalLoadConfig := loadConfig
+	originalSince := syncFlagSince
+	defer func() {
+		loadConfig = originalLoadConfig
+		syncFlagSince = originalSince
+	}()
+
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var (
+		requests   []misskey.GetUserNotesRequest
+		fetchAfter bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetchAfter {
+			t.Fatalf("unexpected request after sync: %s", r.URL.Path)
+		}
+		switch r.URL.Path {
+		case "/api/i":
+			_, _ = w.Write([]byte(`{"id":"user-1","username":"soli"}`))
+		case "/api/users/notes":
+			var req misskey.GetUserNotesRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			requests = append(requests, req)
+
+			count, offset := 0, 0
+			switch req.SinceID {
+			case "":
+				count = syncPageSize
+			case fmt.Sprintf("note-%03d", syncPageSize-1):
+				count, offset = 2, syncPageSize
+			}
+			notes := make([]map[string]any, 0, count)
+			for i := range count {
+				notes = append(notes, map[string]any{
+					"id":        fmt.Sprintf("note-%03d", offset+i),
+					"createdAt": since.Add(time.Duration(offset+i) * time.Hour).Format(time.RFC3339),
+					"userId":    "user-1",
+					"text":      "hello",
+				})
+			}
+			_ = json.NewEncoder(w).Encode(notes)
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.Misskey.InstanceURL = server.URL
+	cfg.Misskey.Token = "token"
+	cfg.Diary.Timezone = "UTC"
+	cfg.Archive.Enabled = true
+	cfg.Archive.Dir = t.TempDir()
+	loadConfig = func() (*config.Config, error) { return cfg, nil }
+	syncFlagSince = "2026-03-01"
+
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+	if err := runSync(cmd, nil); err != nil {
+		t.Fatalf("runSync() error = %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("len(requests) = %d, want 2", len(requests))
+	}
+	if requests[0].SinceDate == nil || *requests[0].SinceDate != since.UnixMilli() {
+		t.Fatalf("first request SinceDate = %v", requests[0].SinceDate)
+	}
+	if !strings.Contains(stderr.String(), "102件のノートを同期しました") {
+		t.Fatalf("stderr = %q", stderr.String())
+	}
+
+	fetchAfter = true
+	notes, err := fetchNotesForWindow(context.Background(), cfg, since.Add(5*time.Hour), since.Add(29*time.Hour))
+	if err != nil {
+		t.Fatalf("fetchNotesForWindow() error = %v", err)
+	}
+	if len(notes) != 24 || notes[0].ID != "note-005" {
+		t.Fatalf("unexpected archived notes: len=%d first=%v", len(notes), notes)
+	}
+}
@@ -12,11 +12,14 @@ import (
 )
 
 type Config struct {
-	Misskey MisskeyConfig `mapstructure:"misskey"`
-	AI      AIConfig      `mapstructure:"ai"`
-	Diary   DiaryConfig   `mapstructure:"diary"`
-	Summaly SummalyConfig `mapstructure:"summaly"`
-	Discord DiscordConfig `mapstructure:"discord"`
+	Misskey   MisskeyConfig   `mapstructure:"misskey"`
+	AI        AIConfig        `mapstructure:"ai"`
+	Diary     DiaryConfig     `mapstructure:"diary"`
+	Summaly   SummalyConfig   `mapstructure:"summaly"`
+	Discord   DiscordConfig   `mapstructure:"discord"`
+	Archive   ArchiveConfig   `mapstructure:"archive"`
+	Privacy   PrivacyConfig   `mapstructure:"privacy"`
+	Redaction RedactionConfig `mapstructure:"redaction"`
 }
 
 type MisskeyConfig struct {
@@ -25,10 +28,16 @@ type MisskeyConfig struct {
 }
 
 type AIConfig struct {
-	DefaultProvider string           `mapstructure:"default_provider"`
+	// DefaultProvider is a provider name or a list tried in order.
+	DefaultProvider []string         `mapstructure:"default_provider"`
 	Claude          AIProviderConfig `mapstructure:"claude"`
 	OpenAI          AIProviderConfig `mapstructure:"openai"`
 	Gemini          AIProviderConfig `mapstructure:"gemini"`
+	Local           LocalAIConfig    `mapstructure:"local"`
+	PromptsDir      string           `mapstructure:"prompts_dir"`
+	Multimodal      bool             `mapstructure:"multimodal"`
+	MaxImages       int              `mapstructure:"max_images"`
+	MaxInputTokens  int              `mapstructure:"max_input_tokens"`
 }
 
 type AIProviderConfig struct {
@@ -36,21 +45,76 @@ type AIProviderConfig struct {
 	Model  string `mapstructure:"model"`
 }
 
+// LocalAIConfig points at an Ollama or other OpenAI-compatible server.
+type LocalAIConfig struct {
+	BaseURL string `mapstructure:"base_url"`
+	Model   string `mapstructure:"model"`
+}
+
 type DiaryConfig struct {
-	OutputDir string `mapstructure:"output_dir"`
-	Author    string `mapstructure:"author"`
-	Editor    string `mapstructure:"editor"`
-	Timezone  string `mapstructure:"timezone"`
+	OutputDir      string             `mapstructure:"output_dir"`
+	Author         string             `mapstructure:"author"`
+	Editor         string             `mapstructure:"editor"`
+	Timezone       string             `mapstructure:"timezone"`
+	Language       string             `mapstructure:"language"`
+	DayStartHour   int                `mapstructure:"day_start_hour"`
+	TimeBuckets    []TimeBucketConfig `mapstructure:"time_buckets"`
+	Template       string             `mapstructure:"template"`
+	PathPattern    string             `mapstructure:"path_pattern"`
+	IncludeReplies bool               `mapstructure:"include_replies"`
+	Channels       []string           `mapstructure:"channels"`
+	IncludeRenotes bool               `mapstructure:"include_renotes"`
+	Attachments    bool               `mapstructure:"attachments"`
+	Gallery        bool               `mapstructure:"gallery"`
+	Sources        bool               `mapstructure:"sources"`
 }
 
+// TimeBucketConfig is a named time-of-day range [Start, End) in hours.
+// An End that is not after Start wraps past midnight.
+type TimeBucketConfig struct {
+	Label string `mapstructure:"label"`
+	Start int    `mapstructure:"start"`
+	End   int    `mapstructure:"end"`
+}
+
+// SummalyConfig controls link summaries. Links are fetched Concurrency at a
+// time and cached for CacheTTL (0 disables the cache). AllowDomains, when set,
+// limits the links to those domains; DenyDomains excludes domains.
 type SummalyConfig struct {
-	Endpoint string `mapstructure:"endpoint"`
+	Endpoint     string        `mapstructure:"endpoint"`
+	Concurrency  int           `mapstructure:"concurrency"`
+	CacheTTL     time.Duration `mapstructure:"cache_ttl"`
+	CacheFile    string        `mapstructure:"cache_file"`
+	AllowDomains []string      `mapstructure:"allow_domains"`
+	DenyDomains  []string      `mapstructure:"deny_domains"`
 }
 
 type DiscordConfig struct {
 	WebhookURL string `mapstructure:"webhook_url"`
 }
 
+type ArchiveConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Dir     string `mapstructure:"dir"`
+}
+
+// RedactionConfig controls the replacement of personal data and secrets with
+// placeholders before notes are sent to AI providers.
+type RedactionConfig struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Words    []string `mapstructure:"words"`
+	Patterns []string `mapstructure:"patterns"`
+	Restore  bool     `mapstructure:"restore"`
+}
+
+// PrivacyConfig controls which notes may leave the machine. Level selects a
+// preset; ExcludeVisibilities and ExcludeCW add to it.
+type PrivacyConfig struct {
+	Level               string   `mapstructure:"level"`
+	ExcludeVisibilities []string `mapstructure:"exclude_visibilities"`
+	ExcludeCW           bool     `mapstructure:"exclude_cw"`
+}
+
 func DefaultConfigDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -106,12 +170,38 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("ai.claude.model", "claude-sonnet-4-6")
 	v.SetDefault("ai.openai.model", "gpt-5.4-mini")
 	v.SetDefault("ai.gemini.model", "gemini-3.1-flash-preview")
+	v.SetDefault("ai.local.base_url", "http://localhost:11434/v1")
+	v.SetDefault("ai.local.model", "")
+	v.SetDefault("ai.prompts_dir", "")
+	v.SetDefault("ai.multimodal", false)
+	v.SetDefault("ai.max_images", 8)
+	v.SetDefault("ai.max_input_tokens", 0)
 	v.SetDefault("diary.output_dir", "./diary")
 	v.SetDefault("diary.author", EnvOrDefault("USER", "Soli"))
 	v.SetDefault("diary.editor", EnvOrDefault("EDITOR", "vim"))
 	v.SetDefault("diary.timezone", "Asia/Tokyo")
+	v.SetDefault("diary.language", "ja")
+	v.SetDefault("diary.day_start_hour", 5)
+	v.SetDefault("diary.template", "")
+	v.SetDefault("diary.path_pattern", "")
+	v.SetDefault("diary.include_replies", false)
+	v.SetDefault("diary.include_renotes", false)
+	v.SetDefault("diary.attachments", false)
+	v.SetDefault("diary.gallery", false)
+	v.SetDefault("diary.sources", false)
 	v.SetDefault("summaly.endpoint", "")
+	v.SetDefault("summaly.concurrency", 4)
+	v.SetDefault("summaly.cache_ttl", "168h")
+	v.SetDefault("summaly.cache_file", "")
+	v.SetDefault("summaly.allow_domains", []string{})
+	v.SetDefault("summaly.deny_domains", []string{"spotify.com"})
 	v.SetDefault("discord.webhook_url", "")
+	v.SetDefault("archive.enabled", false)
+	v.SetDefault("archive.dir", "")
+	v.SetDefault("privacy.level", "normal")
+	v.SetDefault("privacy.exclude_cw", false)
+	v.SetDefault("redaction.enabled", false)
+	v.SetDefault("redaction.restore", true)
 }
 
 func bindEnv(v *viper.Viper) {
@@ -142,3 +232,67 @@ func (c *Config) DiaryLocation() (*time.Location, error) {
 	}
 	return loc, nil
 }
+
+// ArchiveDir returns archive.dir, falling back to the archive directory under the config dir.
+func (c *Config) ArchiveDir() (string, error) {
+	if dir := strings.TrimSpace(c.Archive.Dir); dir != "" {
+		return dir, nil
+	}
+
+	configDir, err := DefaultConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "archive"), nil
+}
+
+// SummalyCacheFile returns summaly.cache_file, falling back to cache/summaly.json under the config dir.
+func (c *Config) SummalyCacheFile() (string, error) {
+	if path := strings.TrimSpace(c.Summaly.CacheFile); path != "" {
+		return path, nil
+	}
+
+	configDir, err := DefaultConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "cache", "summaly.json"), nil
+}
+
+// PromptsDir returns ai.prompts_dir, falling back to the prompts directory under the config dir.
+func (c *Config) PromptsDir() (string, error) {
+	if dir := strings.TrimSpace(c.AI.PromptsDir); dir != "" {
+		return dir, nil
+	}
+
+	configDir, err := DefaultConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "prompts"), nil
+}
+
+// DiaryDayStartHour returns diary.day_start_hour, the hour at which a diary day begins.
+func (c *Config) DiaryDayStartHour() (int, error) {
+	hour := c.Diary.DayStartHour
+	if hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("invalid diary.day_start_hour %d (expected 0-23)", hour)
+	}
+	return hour, nil
+}
+
+// ValidateTimeBuckets checks diary.time_buckets for missing labels and out-of-range hours.
+func (c *Config) ValidateTimeBuckets() error {
+	for i, b := range c.Diary.TimeBuckets {
+		if strings.TrimSpace(b.Label) == "" {
+			return fmt.Errorf("invalid diary.time_buckets[%d]: label is required", i)
+		}
+		if b.Start < 0 || b.Start > 23 {
+			return fmt.Errorf("invalid diary.time_buckets[%d]: start %d (expected 0-23)", i, b.Start)
+		}
+		if b.End < 0 || b.End > 24 {
+			return fmt.Errorf("invalid diary.time_buckets[%d]: end %d (expected 0-24)", i, b.End)
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -16,16 +17,39 @@ func TestSetDefaults(t *testing.T) {
 	setDefaults(v)
 
 	checks := map[string]string{
-		"ai.default_provider": "claude",
-		"ai.claude.model":     "claude-sonnet-4-6",
-		"ai.openai.model":     "gpt-5.4-mini",
-		"ai.gemini.model":     "gemini-3.1-flash-preview",
-		"diary.output_dir":    "./diary",
-		"diary.author":        "TestUser",
-		"diary.editor":        "helix",
-		"diary.timezone":      "Asia/Tokyo",
-		"summaly.endpoint":    "",
-		"discord.webhook_url": "",
+		"ai.default_provider":   "claude",
+		"ai.claude.model":       "claude-sonnet-4-6",
+		"ai.openai.model":       "gpt-5.4-mini",
+		"ai.gemini.model":       "gemini-3.1-flash-preview",
+		"ai.local.base_url":     "http://localhost:11434/v1",
+		"ai.local.model":        "",
+		"ai.prompts_dir":        "",
+		"ai.multimodal":         "false",
+		"ai.max_images":         "8",
+		"ai.max_input_tokens":   "0",
+		"diary.output_dir":      "./diary",
+		"diary.author":          "TestUser",
+		"diary.editor":          "helix",
+		"diary.timezone":        "Asia/Tokyo",
+		"diary.language":        "ja",
+		"diary.day_start_hour":  "5",
+		"diary.template":        "",
+		"diary.path_pattern":    "",
+		"diary.include_replies": "false",
+		"diary.include_renotes": "false",
+		"diary.attachments":     "false",
+		"diary.gallery":         "false",
+		"diary.sources":         "false",
+		"summaly.endpoint":      "",
+		"summaly.concurrency":   "4",
+		"summaly.cache_ttl":     "168h",
+		"summaly.cache_file":    "",
+		"discord.webhook_url":   "",
+		"archive.dir":           "",
+		"privacy.level":         "normal",
+		"privacy.exclude_cw":    "false",
+		"redaction.enabled":     "false",
+		"redaction.restore":     "true",
 	}
 
 	for key, want := range checks {
@@ -91,7 +115,29 @@ discord:
 	if cfg.Discord.WebhookURL != "https://discord.example/env" {
 		t.Fatalf("Discord.WebhookURL = %q", cfg.Discord.WebhookURL)
 	}
-	if cfg.AI.DefaultProvider != "openai" {
+	if len(cfg.AI.DefaultProvider) != 1 || cfg.AI.DefaultProvider[0] != "openai" {
+		t.Fatalf("AI.DefaultProvider = %q", cfg.AI.DefaultProvider)
+	}
+}
+
+func TestLoadProviderList(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configDir := filepath.Join(home, ".config", "diary-cli")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	content := []byte("ai:\n  default_provider: [claude, gemini]\n")
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), content, 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.AI.DefaultProvider) != 2 || cfg.AI.DefaultProvider[0] != "claude" || cfg.AI.DefaultProvider[1] != "gemini" {
 		t.Fatalf("AI.DefaultProvider = %q", cfg.AI.DefaultProvider)
 	}
 }
@@ -107,9 +153,15 @@ func TestLoadFallsBackWhenConfigFileMissing(t *testing.T) {
 		t.Fatalf("Load() error = %v", err)
 	}
 
-	if cfg.AI.DefaultProvider != "claude" {
+	if len(cfg.AI.DefaultProvider) != 1 || cfg.AI.DefaultProvider[0] != "claude" {
 		t.Fatalf("AI.DefaultProvider = %q", cfg.AI.DefaultProvider)
 	}
+	if cfg.Summaly.CacheTTL != 168*time.Hour {
+		t.Fatalf("Summaly.CacheTTL = %v", cfg.Summaly.CacheTTL)
+	}
+	if len(cfg.Summaly.DenyDomains) != 1 || cfg.Summaly.DenyDomains[0] != "spotify.com" {
+		t.Fatalf("Summaly.DenyDomains = %q", cfg.Summaly.DenyDomains)
+	}
 	if cfg.AI.Claude.Model != "claude-sonnet-4-6" {
 		t.Fatalf("AI.Claude.Model = %q", cfg.AI.Claude.Model)
 	}
@@ -153,3 +205,86 @@ func TestDiaryLocationInvalid(t *testing.T) {
 		t.Fatal("DiaryLocation() error = nil, want invalid timezone error")
 	}
 }
+
+func TestArchiveDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := &Config{}
+	dir, err := cfg.ArchiveDir()
+	if err != nil {
+		t.Fatalf("ArchiveDir() error = %v", err)
+	}
+	if want := filepath.Join(home, ".config", "diary-cli", "archive"); dir != want {
+		t.Fatalf("ArchiveDir() = %q, want %q", dir, want)
+	}
+	if dir, _ := cfg.PromptsDir(); dir != filepath.Join(home, ".config", "diary-cli", "prompts") {
+		t.Fatalf("PromptsDir() = %q", dir)
+	}
+	if path, _ := cfg.SummalyCacheFile(); path != filepath.Join(home, ".config", "diary-cli", "cache", "summaly.json") {
+		t.Fatalf("SummalyCacheFile() = %q", path)
+	}
+
+	cfg.Archive.Dir = "/var/lib/diary-cli"
+	if dir, _ := cfg.ArchiveDir(); dir != "/var/lib/diary-cli" {
+		t.Fatalf("ArchiveDir() = %q", dir)
+	}
+}
+
+func TestLoadTimeBuckets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configDir := filepath.Join(home, ".config", "diary-cli")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	content := []byte(`diary:
+  day_start_hour: 12
+  time_buckets:
+    - label: 出勤前
+      start: 12
+      end: 18
+    - label: 勤務中
+      start: 18
+      end: 6
+`)
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), content, 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if hour, err := cfg.DiaryDayStartHour(); err != nil || hour != 12 {
+		t.Fatalf("DiaryDayStartHour() = %d, %v", hour, err)
+	}
+	if len(cfg.Diary.TimeBuckets) != 2 {
+		t.Fatalf("len(TimeBuckets) = %d, want 2", len(cfg.Diary.TimeBuckets))
+	}
+	if cfg.Diary.TimeBuckets[1] != (TimeBucketConfig{Label: "勤務中", Start: 18, End: 6}) {
+		t.Fatalf("TimeBuckets[1] = %#v", cfg.Diary.TimeBuckets[1])
+	}
+	if err := cfg.ValidateTimeBuckets(); err != nil {
+		t.Fatalf("ValidateTimeBuckets() error = %v", err)
+	}
+}
+
+func TestDiaryDayStartHourAndTimeBucketsInvalid(t *testing.T) {
+	cfg := &Config{}
+	cfg.Diary.DayStartHour = 24
+	if _, err := cfg.DiaryDayStartHour(); err == nil {
+		t.Fatal("DiaryDayStartHour() error = nil, want out of range error")
+	}
+
+	cfg.Diary.TimeBuckets = []TimeBucketConfig{{Label: "", Start: 0, End: 6}}
+	if err := cfg.ValidateTimeBuckets(); err == nil {
+		t.Fatal("ValidateTimeBuckets() error = nil, want missing label error")
+	}
+	cfg.Diary.TimeBuckets = []TimeBucketConfig{{Label: "x", Start: 0, End: 25}}
+	if err := cfg.ValidateTimeBuckets(); err == nil {
+		t.Fatal("ValidateTimeBuckets() error = nil, want out of range error")
+	}
+}
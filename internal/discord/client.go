@@ -2,19 +2,36 @@ package discord
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/soli0222/diary-cli/internal/httpretry"
+	"github.com/soli0222/diary-cli/internal/i18n"
 )
 
 type Client struct {
 	webhookURL string
 	httpClient *http.Client
+	retry      httpretry.Policy
+	messages   *i18n.Messages
 }
 
-const maxDescriptionLength = 4096
+const (
+	maxDescriptionLength = 4096
+	maxFieldValueLength  = 1024
+)
+
+// Link is a labelled URL listed in the sources field of the embed.
+type Link struct {
+	Label string
+	URL   string
+}
 
 type webhookMessage struct {
 	Username string         `json:"username,omitempty"`
@@ -35,28 +52,68 @@ type discordField struct {
 	Inline bool   `json:"inline,omitempty"`
 }
 
-func NewClient(webhookURL string) *Client {
+// webhookRetry only retries rate limits: a webhook POST that failed with a
+// network error or 5xx may still have been posted, and repeating it would
+// post the diary twice.
+var webhookRetry = httpretry.Policy{
+	MaxAttempts:   4,
+	BaseDelay:     time.Second,
+	MaxDelay:      30 * time.Second,
+	RateLimitOnly: true,
+}
+
+func NewClient(webhookURL string, msg *i18n.Messages) *Client {
+	if msg == nil {
+		msg = i18n.Default()
+	}
 	return &Client{
 		webhookURL: webhookURL,
 		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retry:      webhookRetry,
+		messages:   msg,
 	}
 }
 
-func (c *Client) PostSummary(date string, noteCount int, title, summary string) error {
+// PostSummary posts the diary summary as an embed. When sources are given, they
+// are listed as links in an extra field, as many as fit.
+func (c *Client) PostSummary(ctx context.Context, date string, noteCount int, title, summary string, sources ...Link) error {
+	fields := []discordField{
+		{Name: c.messages.TitleLabel, Value: title},
+		{Name: c.messages.NoteCountLabel, Value: fmt.Sprintf("%d", noteCount), Inline: true},
+	}
+	if value := linkList(sources); value != "" {
+		fields = append(fields, discordField{Name: c.messages.SourcesHeading, Value: value})
+	}
+
 	payload := webhookMessage{
 		Username: "diary-cli",
 		Embeds: []discordEmbed{{
-			Title:       fmt.Sprintf("%s のMisskeyサマリー", date),
+			Title:       fmt.Sprintf(c.messages.DiscordTitle, date),
 			Description: truncate(summary, maxDescriptionLength),
 			Color:       0x86b300,
 			Timestamp:   time.Now().Format(time.RFC3339),
-			Fields: []discordField{
-				{Name: "タイトル", Value: title},
-				{Name: "ノート数", Value: fmt.Sprintf("%d", noteCount), Inline: true},
-			},
+			Fields:      fields,
 		}},
 	}
-	return c.send(payload)
+	return c.send(ctx, payload)
+}
+
+// linkList renders links one per line, dropping those that would exceed the
+// field value limit.
+func linkList(links []Link) string {
+	var sb strings.Builder
+	for i, l := range links {
+		line := fmt.Sprintf("[%s](%s)", l.Label, l.URL)
+		if i > 0 {
+			line = "\n" + line
+		}
+		if utf8.RuneCountInString(sb.String()+line) > maxFieldValueLength-2 {
+			sb.WriteString("\n…")
+			break
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
 }
 
 func truncate(s string, max int) string {
@@ -70,27 +127,25 @@ func truncate(s string, max int) string {
 	return string(runes[:max-3]) + "..."
 }
 
-func (c *Client) send(message webhookMessage) error {
+func (c *Client) send(ctx context.Context, message webhookMessage) error {
 	body, err := json.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("failed to marshal webhook payload: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.webhookURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.retry.Do(c.httpClient, req)
+	var statusErr *httpretry.StatusError
+	if errors.As(err, &statusErr) {
+		return fmt.Errorf("discord API error: %w", err)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		responseBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("discord API error: %d - %s", resp.StatusCode, string(responseBody))
-	}
-	return nil
+	return resp.Body.Close()
 }
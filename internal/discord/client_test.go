@@ -2,6 +2,7 @@ package discord
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -17,7 +18,7 @@ func TestPostSummaryBuildsWebhookPayload(t *testing.T) {
 		gotPayload     webhookMessage
 	)
 
-	client := NewClient("https://discord.example/webhook")
+	client := NewClient("https://discord.example/webhook", nil)
 	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
 		gotMethod = r.Method
 		gotContentType = r.Header.Get("Content-Type")
@@ -31,7 +32,7 @@ func TestPostSummaryBuildsWebhookPayload(t *testing.T) {
 		}, nil
 	})}
 	longSummary := strings.Repeat("あ", maxDescriptionLength+10)
-	if err := client.PostSummary("2026-02-23", 42, "一日のタイトル", longSummary); err != nil {
+	if err := client.PostSummary(context.Background(), "2026-02-23", 42, "一日のタイトル", longSummary); err != nil {
 		t.Fatalf("PostSummary() error = %v", err)
 	}
 
@@ -75,8 +76,59 @@ func TestPostSummaryBuildsWebhookPayload(t *testing.T) {
 	}
 }
 
+func TestPostSummaryListsSources(t *testing.T) {
+	var gotPayload webhookMessage
+	client := NewClient("https://discord.example/webhook", nil)
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		return &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(bytes.NewReader(nil)), Header: make(http.Header)}, nil
+	})}
+
+	links := make([]Link, 100)
+	for i := range links {
+		links[i] = Link{Label: "09:05", URL: "https://misskey.example/notes/" + strings.Repeat("x", 10)}
+	}
+	if err := client.PostSummary(context.Background(), "2026-02-23", 100, "title", "summary", links...); err != nil {
+		t.Fatalf("PostSummary() error = %v", err)
+	}
+
+	fields := gotPayload.Embeds[0].Fields
+	if len(fields) != 3 || fields[2].Name != "元のノート" {
+		t.Fatalf("Fields = %#v", fields)
+	}
+	value := fields[2].Value
+	if !strings.HasPrefix(value, "[09:05](https://misskey.example/notes/xxxxxxxxxx)\n") || !strings.HasSuffix(value, "\n…") {
+		t.Fatalf("sources value = %q", value)
+	}
+	if n := len([]rune(value)); n > maxFieldValueLength {
+		t.Fatalf("sources value length = %d, want <= %d", n, maxFieldValueLength)
+	}
+}
+
 type roundTripFunc func(*http.Request) (*http.Response, error)
 
 func (fn roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
 	return fn(r)
 }
+
+func TestPostSummaryDoesNotRepeatServerErrors(t *testing.T) {
+	calls := 0
+	client := NewClient("https://discord.example/webhook", nil)
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusBadGateway,
+			Body:       io.NopCloser(strings.NewReader("bad gateway")),
+			Header:     make(http.Header),
+		}, nil
+	})}
+
+	if err := client.PostSummary(context.Background(), "2026-02-23", 1, "タイトル", "本文"); err == nil {
+		t.Fatal("PostSummary() error = nil, want error")
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
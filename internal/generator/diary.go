@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/soli0222/diary-cli/internal/i18n"
 	"github.com/soli0222/diary-cli/internal/models"
 )
 
@@ -15,6 +16,7 @@ type JSONOutput struct {
 	NoteCount int              `json:"note_count"`
 	Title     string           `json:"title"`
 	Summary   string           `json:"summary"`
+	Provider  string           `json:"provider,omitempty"`
 	Notes     []JSONOutputNote `json:"notes"`
 }
 
@@ -24,33 +26,82 @@ type JSONOutputNote struct {
 	Text      string `json:"text"`
 }
 
-func BuildMarkdown(date time.Time, author, title, summary string) string {
-	dateStr := date.Format("2006-01-02")
+// NewMarkdownData fills the language-dependent template variables from msg.
+func NewMarkdownData(msg *i18n.Messages, date time.Time, author, title, summary string, noteCount int) MarkdownData {
+	if msg == nil {
+		msg = i18n.Default()
+	}
+	return MarkdownData{
+		Date:      date,
+		Author:    author,
+		Title:     title,
+		Summary:   summary,
+		Heading:   msg.SummaryHeading,
+		Category:  msg.DiaryCategory,
+		NoteCount: noteCount,
+		Language:  msg.Language,
+
+		GalleryHeading: msg.GalleryHeading,
+		SourcesHeading: msg.SourcesHeading,
+	}
+}
+
+// BuildDigestMarkdown renders a weekly or monthly digest with its own front matter.
+func BuildDigestMarkdown(date time.Time, author, period, heading, category, title, summary string) string {
 	timeStr := date.Format("2006-01-02T15:04")
 
 	var sb strings.Builder
 	sb.WriteString("---\n")
-	fmt.Fprintf(&sb, "title: %s\n", dateStr)
+	fmt.Fprintf(&sb, "title: %s\n", period)
 	fmt.Fprintf(&sb, "author: %s\n", author)
 	sb.WriteString("layout: post\n")
 	fmt.Fprintf(&sb, "date: %s\n", timeStr)
-	sb.WriteString("category: 日記\n")
+	fmt.Fprintf(&sb, "category: %s\n", category)
 	sb.WriteString("---\n\n")
 	fmt.Fprintf(&sb, "# %s\n\n", title)
-	sb.WriteString("# Misskeyサマリー\n\n")
+	fmt.Fprintf(&sb, "# %s\n\n", heading)
 	sb.WriteString(strings.TrimSpace(summary))
 	sb.WriteString("\n")
 
 	return sb.String()
 }
 
-func BuildSummaryText(date time.Time, noteCount int, title, summary string) string {
+// StripFrontMatter returns the Markdown body without a leading YAML front matter
+// block or generated section markers.
+func StripFrontMatter(markdown string) string {
+	normalized := strings.ReplaceAll(markdown, "\r\n", "\n")
+	normalized = strings.NewReplacer(GeneratedBegin+"\n", "", "\n"+GeneratedEnd, "").Replace(normalized)
+	if !strings.HasPrefix(normalized, "---\n") {
+		return strings.TrimSpace(normalized)
+	}
+
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		if strings.HasSuffix(rest, "\n---") {
+			return ""
+		}
+		return strings.TrimSpace(normalized)
+	}
+	return strings.TrimSpace(rest[end+len("\n---\n"):])
+}
+
+func BuildSummaryText(msg *i18n.Messages, date time.Time, noteCount int, title, summary string) string {
+	return BuildSummaryHeader(msg, date, noteCount, title) + "\n\n" + strings.TrimSpace(summary)
+}
+
+// BuildSummaryHeader returns the date, note count and title lines of BuildSummaryText.
+func BuildSummaryHeader(msg *i18n.Messages, date time.Time, noteCount int, title string) string {
+	if msg == nil {
+		msg = i18n.Default()
+	}
 	return fmt.Sprintf(
-		"%s のサマリー\nノート数: %d\nタイトル: %s\n\n%s",
-		date.Format("2006-01-02"),
+		"%s\n%s: %d\n%s: %s",
+		fmt.Sprintf(msg.SummaryTextHeader, date.Format("2006-01-02")),
+		msg.NoteCountLabel,
 		noteCount,
+		msg.TitleLabel,
 		title,
-		strings.TrimSpace(summary),
 	)
 }
 
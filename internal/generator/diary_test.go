@@ -5,12 +5,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/soli0222/diary-cli/internal/i18n"
 	"github.com/soli0222/diary-cli/internal/models"
 )
 
-func TestBuildMarkdown(t *testing.T) {
+func TestDefaultMarkdownTemplateRender(t *testing.T) {
 	date := time.Date(2026, 2, 15, 5, 0, 0, 0, time.FixedZone("Asia/Tokyo", 9*60*60))
-	result := BuildMarkdown(date, "TestUser", "テストの一日", "テストに関するサマリー。")
+	result := renderDefault(t, nil, date, "TestUser", "テストの一日", "テストに関するサマリー。")
 
 	checks := []string{
 		"---\n",
@@ -26,23 +27,57 @@ func TestBuildMarkdown(t *testing.T) {
 
 	for _, expected := range checks {
 		if !strings.Contains(result, expected) {
-			t.Fatalf("BuildMarkdown() missing %q\nGot:\n%s", expected, result)
+			t.Fatalf("Render() missing %q\nGot:\n%s", expected, result)
 		}
 	}
 }
 
-func TestBuildMarkdown_DoesNotIncludeDiaryBodySection(t *testing.T) {
+func TestDefaultMarkdownTemplate_DoesNotIncludeDiaryBodySection(t *testing.T) {
 	date := time.Date(2026, 2, 15, 10, 0, 0, 0, time.UTC)
-	result := BuildMarkdown(date, "User", "Title", "Summary")
+	result := renderDefault(t, nil, date, "User", "Title", "Summary")
 
 	if strings.Count(result, "# ") != 2 {
 		t.Fatalf("expected exactly two headings, got:\n%s", result)
 	}
 }
 
+func TestBuildDigestMarkdown(t *testing.T) {
+	date := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	result := BuildDigestMarkdown(date, "TestUser", "2026-W41", "週間サマリー", "週報", "秋の一週間", "まとめ。")
+
+	for _, expected := range []string{
+		"title: 2026-W41\n",
+		"author: TestUser\n",
+		"date: 2026-10-05T00:00\n",
+		"category: 週報\n",
+		"# 秋の一週間\n",
+		"# 週間サマリー\n",
+		"まとめ。\n",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Fatalf("BuildDigestMarkdown() missing %q\nGot:\n%s", expected, result)
+		}
+	}
+}
+
+func TestStripFrontMatter(t *testing.T) {
+	date := time.Date(2026, 2, 15, 5, 0, 0, 0, time.UTC)
+	markdown := renderDefault(t, nil, date, "User", "Title", "Summary")
+
+	got := StripFrontMatter(markdown)
+	want := "# Title\n\n# Misskeyサマリー\n\nSummary"
+	if got != want {
+		t.Fatalf("StripFrontMatter() = %q, want %q", got, want)
+	}
+
+	if got := StripFrontMatter("plain body\n"); got != "plain body" {
+		t.Fatalf("StripFrontMatter(no front matter) = %q", got)
+	}
+}
+
 func TestBuildSummaryText(t *testing.T) {
 	date := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
-	got := BuildSummaryText(date, 42, "タイトル", "本文")
+	got := BuildSummaryText(nil, date, 42, "タイトル", "本文")
 
 	for _, expected := range []string{"2026-02-15 のサマリー", "ノート数: 42", "タイトル: タイトル", "本文"} {
 		if !strings.Contains(got, expected) {
@@ -51,6 +86,26 @@ func TestBuildSummaryText(t *testing.T) {
 	}
 }
 
+func TestBuildSummaryTextEnglish(t *testing.T) {
+	msg, err := i18n.For(i18n.English)
+	if err != nil {
+		t.Fatalf("i18n.For() error = %v", err)
+	}
+
+	got := BuildSummaryText(msg, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), 3, "A day", "Body")
+	want := "Summary for 2026-02-15\nNotes: 3\nTitle: A day\n\nBody"
+	if got != want {
+		t.Fatalf("BuildSummaryText() = %q, want %q", got, want)
+	}
+
+	markdown := renderDefault(t, msg, time.Date(2026, 2, 15, 5, 0, 0, 0, time.UTC), "User", "A day", "Body")
+	for _, expected := range []string{"category: Diary\n", "# Misskey Summary\n"} {
+		if !strings.Contains(markdown, expected) {
+			t.Fatalf("Render() missing %q\nGot:\n%s", expected, markdown)
+		}
+	}
+}
+
 func TestBuildJSONOutput(t *testing.T) {
 	start := time.Date(2026, 2, 15, 5, 0, 0, 0, time.UTC)
 	end := start.Add(24 * time.Hour)
@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GalleryImage is an image shown in the gallery section of a diary.
+type GalleryImage struct {
+	// Path is relative to the diary file and uses forward slashes.
+	Path string
+	Alt  string
+}
+
+// GalleryDir returns the directory, relative to diary.output_dir, that holds the
+// images of the diary at relPath: 2026/0215.md keeps its images in 2026/0215.
+func GalleryDir(relPath string) string {
+	return strings.TrimSuffix(relPath, filepath.Ext(relPath))
+}
+
+// NewGalleryImage returns the gallery entry for fileName saved in the gallery
+// directory of the diary at relPath.
+func NewGalleryImage(relPath, fileName, alt string) GalleryImage {
+	dir := filepath.ToSlash(filepath.Base(GalleryDir(relPath)))
+	return GalleryImage{
+		Path: path.Join(dir, fileName),
+		Alt:  galleryAlt(alt),
+	}
+}
+
+// galleryAlt flattens alt text so it cannot break out of ![...].
+func galleryAlt(alt string) string {
+	alt = strings.NewReplacer("[", "(", "]", ")", "\r", " ", "\n", " ").Replace(alt)
+	return strings.Join(strings.Fields(alt), " ")
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Markers delimiting the generated section of a diary. Anything outside them
+// is left alone when a diary is regenerated in merge mode.
+const (
+	GeneratedBegin = "<!-- diary-cli:begin -->"
+	GeneratedEnd   = "<!-- diary-cli:end -->"
+)
+
+// ErrNoGeneratedSection is returned when a diary has no generated section markers.
+var ErrNoGeneratedSection = errors.New("no diary-cli:begin/end markers found")
+
+// MergeGenerated replaces the generated section of existing with the one in generated.
+func MergeGenerated(existing, generated string) (string, error) {
+	gStart, gEnd, ok := generatedSection(generated)
+	if !ok {
+		return "", fmt.Errorf("diary template: %w", ErrNoGeneratedSection)
+	}
+	eStart, eEnd, ok := generatedSection(existing)
+	if !ok {
+		return "", fmt.Errorf("existing diary: %w", ErrNoGeneratedSection)
+	}
+	return existing[:eStart] + generated[gStart:gEnd] + existing[eEnd:], nil
+}
+
+// AppendGenerated adds the generated content to the end of existing without
+// touching what is already there. The appended part carries no markers, so a
+// later merge still targets the original section.
+func AppendGenerated(existing, generated string) string {
+	section := StripFrontMatter(generated)
+	if start, end, ok := generatedSection(generated); ok {
+		section = strings.TrimSpace(generated[start+len(GeneratedBegin) : end-len(GeneratedEnd)])
+	}
+	return strings.TrimRight(existing, "\n") + "\n\n" + section + "\n"
+}
+
+// HasGeneratedSection reports whether content has the markers MergeGenerated needs.
+func HasGeneratedSection(content string) bool {
+	_, _, ok := generatedSection(content)
+	return ok
+}
+
+func generatedSection(content string) (start, end int, ok bool) {
+	start = strings.Index(content, GeneratedBegin)
+	if start < 0 {
+		return 0, 0, false
+	}
+	n := strings.Index(content[start:], GeneratedEnd)
+	if n < 0 {
+		return 0, 0, false
+	}
+	return start, start + n + len(GeneratedEnd), true
+}
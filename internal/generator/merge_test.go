@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMergeGenerated(t *testing.T) {
+	existing := "---\ntitle: x\n---\n\nmemo above\n\n<!-- diary-cli:begin -->\nold\n<!-- diary-cli:end -->\n\nmemo below\n"
+	generated := "---\ntitle: y\n---\n\n<!-- diary-cli:begin -->\nnew\n<!-- diary-cli:end -->\n"
+
+	got, err := MergeGenerated(existing, generated)
+	if err != nil {
+		t.Fatalf("MergeGenerated() error = %v", err)
+	}
+	want := "---\ntitle: x\n---\n\nmemo above\n\n<!-- diary-cli:begin -->\nnew\n<!-- diary-cli:end -->\n\nmemo below\n"
+	if got != want {
+		t.Fatalf("MergeGenerated() = %q, want %q", got, want)
+	}
+
+	if _, err := MergeGenerated("handwritten\n", generated); !errors.Is(err, ErrNoGeneratedSection) {
+		t.Fatalf("err = %v, want ErrNoGeneratedSection", err)
+	}
+	if _, err := MergeGenerated(existing, "no markers\n"); !errors.Is(err, ErrNoGeneratedSection) {
+		t.Fatalf("err = %v, want ErrNoGeneratedSection", err)
+	}
+}
+
+func TestAppendGenerated(t *testing.T) {
+	generated := "---\ntitle: y\n---\n\n<!-- diary-cli:begin -->\nnew\n<!-- diary-cli:end -->\n"
+
+	got := AppendGenerated("handwritten\n\n", generated)
+	if want := "handwritten\n\nnew\n"; got != want {
+		t.Fatalf("AppendGenerated() = %q, want %q", got, want)
+	}
+
+	got = AppendGenerated("handwritten", "---\ntitle: y\n---\n\nplain\n")
+	if want := "handwritten\n\nplain\n"; got != want {
+		t.Fatalf("AppendGenerated() without markers = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+// Source links a diary back to one of the notes it was generated from.
+type Source struct {
+	ID   string
+	Time string
+	URL  string
+}
+
+var citationPattern = regexp.MustCompile(`( ?)\[note:([^\]\s]+)\]`)
+
+// NoteURL returns the permalink of a note on instanceURL.
+func NoteURL(instanceURL, noteID string) string {
+	return strings.TrimRight(instanceURL, "/") + "/notes/" + noteID
+}
+
+// BuildSources lists notes chronologically with their local time and permalink.
+func BuildSources(instanceURL string, notes []models.Note, loc *time.Location) []Source {
+	sorted := append([]models.Note(nil), notes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	sources := make([]Source, 0, len(sorted))
+	for _, n := range sorted {
+		sources = append(sources, Source{
+			ID:   n.ID,
+			Time: n.CreatedAt.In(loc).Format("15:04"),
+			URL:  NoteURL(instanceURL, n.ID),
+		})
+	}
+	return sources
+}
+
+// LinkCitations rewrites "[note:ID]" citations into Markdown links to the
+// matching source. Citations of unknown notes are removed.
+func LinkCitations(text string, sources []Source) string {
+	byID := make(map[string]Source, len(sources))
+	for _, s := range sources {
+		byID[s.ID] = s
+	}
+
+	return citationPattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := citationPattern.FindStringSubmatch(match)
+		s, ok := byID[groups[2]]
+		if !ok {
+			return ""
+		}
+		return groups[1] + "[" + s.Time + "](" + s.URL + ")"
+	})
+}
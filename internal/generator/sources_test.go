@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+func TestBuildSourcesAndLinkCitations(t *testing.T) {
+	loc := time.FixedZone("Asia/Tokyo", 9*60*60)
+	notes := []models.Note{
+		{ID: "b2", CreatedAt: time.Date(2026, 2, 15, 3, 30, 0, 0, time.UTC)},
+		{ID: "a1", CreatedAt: time.Date(2026, 2, 15, 0, 5, 0, 0, time.UTC)},
+	}
+
+	sources := BuildSources("https://misskey.example/", notes, loc)
+
+	want := []Source{
+		{ID: "a1", Time: "09:05", URL: "https://misskey.example/notes/a1"},
+		{ID: "b2", Time: "12:30", URL: "https://misskey.example/notes/b2"},
+	}
+	if len(sources) != len(want) || sources[0] != want[0] || sources[1] != want[1] {
+		t.Fatalf("BuildSources() = %#v, want %#v", sources, want)
+	}
+
+	got := LinkCitations("朝に散歩した [note:a1]。昼はラーメン [note:b2][note:zz]。", sources)
+	wantText := "朝に散歩した [09:05](https://misskey.example/notes/a1)。昼はラーメン [12:30](https://misskey.example/notes/b2)。"
+	if got != wantText {
+		t.Fatalf("LinkCitations() = %q, want %q", got, wantText)
+	}
+	if got := LinkCitations("散歩 [note:a1]", nil); got != "散歩" {
+		t.Fatalf("LinkCitations(nil) = %q, want citations removed", got)
+	}
+}
+
+func TestDefaultMarkdownTemplateWithSources(t *testing.T) {
+	data := NewMarkdownData(nil, time.Date(2026, 2, 15, 5, 0, 0, 0, time.UTC), "u", "t", "s", 1)
+	data.Sources = []Source{{ID: "a1", Time: "09:05", URL: "https://misskey.example/notes/a1"}}
+
+	got, err := DefaultMarkdownTemplate().Render(data)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := "s\n\n# 元のノート\n\n- [09:05](https://misskey.example/notes/a1)\n<!-- diary-cli:end -->\n"
+	if !strings.HasSuffix(got, want) {
+		t.Fatalf("Render() = %q, want suffix %q", got, want)
+	}
+}
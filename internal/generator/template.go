@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultPathPattern reproduces the YYYY/MMDD.md layout.
+const DefaultPathPattern = "{{.Year}}/{{.Month}}{{.Day}}.md"
+
+//go:embed templates/diary.md.tmpl
+var defaultTemplateFS embed.FS
+
+// MarkdownData holds the variables available to diary Markdown templates.
+type MarkdownData struct {
+	Date      time.Time
+	Author    string
+	Title     string
+	Summary   string
+	Heading   string
+	Category  string
+	NoteCount int
+	Language  string
+
+	// Images is the optional gallery; GalleryHeading is its section heading.
+	Images         []GalleryImage
+	GalleryHeading string
+
+	// Sources lists permalinks to the notes; SourcesHeading is its section heading.
+	Sources        []Source
+	SourcesHeading string
+}
+
+// PathData holds the variables available to diary path patterns.
+type PathData struct {
+	Date  string
+	Year  string
+	Month string
+	Day   string
+}
+
+// MarkdownTemplate renders a diary entry, including its front matter.
+type MarkdownTemplate struct {
+	tmpl *template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"quote": quoteYAML,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	"replace": func(old, new, s string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
+}
+
+// DefaultMarkdownTemplate returns the built-in Jekyll-style template.
+func DefaultMarkdownTemplate() *MarkdownTemplate {
+	source, err := defaultTemplateFS.ReadFile("templates/diary.md.tmpl")
+	if err != nil {
+		panic(err)
+	}
+	tmpl, err := parseMarkdownTemplate("diary.md.tmpl", string(source))
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+// LoadMarkdownTemplate parses a user-supplied template file. An empty path yields the default.
+func LoadMarkdownTemplate(path string) (*MarkdownTemplate, error) {
+	if strings.TrimSpace(path) == "" {
+		return DefaultMarkdownTemplate(), nil
+	}
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read diary template: %w", err)
+	}
+	return parseMarkdownTemplate(filepath.Base(path), string(source))
+}
+
+func parseMarkdownTemplate(name, source string) (*MarkdownTemplate, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse diary template %s: %w", name, err)
+	}
+	return &MarkdownTemplate{tmpl: tmpl}, nil
+}
+
+// Render executes the template with data.
+func (t *MarkdownTemplate) Render(data MarkdownData) (string, error) {
+	data.Summary = strings.TrimSpace(data.Summary)
+
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render diary template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// DiaryPath renders a path pattern for date. The result must stay inside the output directory.
+func DiaryPath(pattern string, date time.Time) (string, error) {
+	if strings.TrimSpace(pattern) == "" {
+		pattern = DefaultPathPattern
+	}
+
+	tmpl, err := template.New("path").Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid diary.path_pattern: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, PathData{
+		Date:  date.Format("2006-01-02"),
+		Year:  date.Format("2006"),
+		Month: date.Format("01"),
+		Day:   date.Format("02"),
+	}); err != nil {
+		return "", fmt.Errorf("invalid diary.path_pattern: %w", err)
+	}
+
+	rel := filepath.Clean(filepath.FromSlash(strings.TrimSpace(buf.String())))
+	if rel == "." || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid diary.path_pattern: %q must resolve to a path inside diary.output_dir", pattern)
+	}
+	return rel, nil
+}
+
+// quoteYAML renders s as a double-quoted YAML scalar.
+func quoteYAML(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return `""`
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/i18n"
+)
+
+func TestDefaultMarkdownTemplate(t *testing.T) {
+	date := time.Date(2026, 2, 15, 5, 0, 0, 0, time.UTC)
+
+	want := "---\ntitle: 2026-02-15\nauthor: TestUser\nlayout: post\ndate: 2026-02-15T05:00\ncategory: 日記\n---\n\n<!-- diary-cli:begin -->\n# タイトル\n\n# Misskeyサマリー\n\nサマリー\n<!-- diary-cli:end -->\n"
+	if got := renderDefault(t, nil, date, "TestUser", "タイトル", "  サマリー\n"); got != want {
+		t.Fatalf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestDefaultMarkdownTemplateWithGallery(t *testing.T) {
+	date := time.Date(2026, 2, 15, 5, 0, 0, 0, time.UTC)
+	images := []GalleryImage{
+		NewGalleryImage(filepath.Join("2026", "0215.md"), "a1.jpg", "夕焼け [川沿い]\n"),
+		NewGalleryImage(filepath.Join("2026", "0215.md"), "b2.png", "cat.png"),
+	}
+
+	want := "<!-- diary-cli:begin -->\n# タイトル\n\n# Misskeyサマリー\n\nサマリー\n\n# 写真\n\n![夕焼け (川沿い)](0215/a1.jpg)\n![cat.png](0215/b2.png)\n<!-- diary-cli:end -->\n"
+	got := renderDefault(t, nil, date, "TestUser", "タイトル", "サマリー", images...)
+	if !strings.HasSuffix(got, want) {
+		t.Fatalf("Render() = %q, want suffix %q", got, want)
+	}
+	if dir := GalleryDir(filepath.Join("2026", "0215.md")); dir != filepath.Join("2026", "0215") {
+		t.Fatalf("GalleryDir() = %q", dir)
+	}
+}
+
+func TestLoadMarkdownTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hugo.md.tmpl")
+	source := "---\ntitle: {{quote .Title}}\nlang: {{.Language}}\nnotes: {{.NoteCount}}\n---\n{{.Summary}}\n"
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tmpl, err := LoadMarkdownTemplate(path)
+	if err != nil {
+		t.Fatalf("LoadMarkdownTemplate() error = %v", err)
+	}
+
+	date := time.Date(2026, 2, 15, 5, 0, 0, 0, time.UTC)
+	got, err := tmpl.Render(NewMarkdownData(i18n.Default(), date, "u", `say "hi" & <bye>`, "body", 3))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := "---\ntitle: \"say \\\"hi\\\" & <bye>\"\nlang: ja\nnotes: 3\n---\nbody\n"
+	if got != want {
+		t.Fatalf("Render() = %q, want %q", got, want)
+	}
+
+	if _, err := LoadMarkdownTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Fatal("expected error for missing template, got nil")
+	}
+}
+
+func TestDiaryPath(t *testing.T) {
+	date := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pattern string
+		want    string
+		wantErr bool
+	}{
+		{pattern: "", want: filepath.Join("2026", "0307.md")},
+		{pattern: "content/posts/{{.Date}}.md", want: filepath.Join("content", "posts", "2026-03-07.md")},
+		{pattern: "{{.Year}}/{{.Month}}/{{.Day}}/index.md", want: filepath.Join("2026", "03", "07", "index.md")},
+		{pattern: "../{{.Date}}.md", wantErr: true},
+		{pattern: "/tmp/{{.Date}}.md", wantErr: true},
+		{pattern: "{{.Unknown}}.md", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := DiaryPath(tt.pattern, date)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("DiaryPath(%q) = %q, want error", tt.pattern, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("DiaryPath(%q) = %q, %v, want %q", tt.pattern, got, err, tt.want)
+		}
+	}
+}
+
+// renderDefault renders a diary entry with the built-in template.
+func renderDefault(t *testing.T, msg *i18n.Messages, date time.Time, author, title, summary string, images ...GalleryImage) string {
+	t.Helper()
+	data := NewMarkdownData(msg, date, author, title, summary, 0)
+	data.Images = images
+	markdown, err := DefaultMarkdownTemplate().Render(data)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	return markdown
+}
@@ -8,14 +8,16 @@ import (
 
 var run = runCommand
 
-// CommitAndPush stages, commits, and pushes the given file.
-func CommitAndPush(repoDir, filePath, date string) error {
-	if err := run(repoDir, "git", "add", "--", filePath); err != nil {
+// CommitAndPush stages, commits, and pushes the given file along with any
+// extra paths (e.g. the diary's image directory).
+func CommitAndPush(repoDir, filePath, date string, extraPaths ...string) error {
+	paths := append([]string{filePath}, extraPaths...)
+	if err := run(repoDir, "git", append([]string{"add", "--"}, paths...)...); err != nil {
 		return fmt.Errorf("git add failed: %w", err)
 	}
 
 	commitMsg := fmt.Sprintf("diary: %s", date)
-	if err := run(repoDir, "git", "commit", "-m", commitMsg, "--only", "--", filePath); err != nil {
+	if err := run(repoDir, "git", append([]string{"commit", "-m", commitMsg, "--only", "--"}, paths...)...); err != nil {
 		return fmt.Errorf("git commit failed: %w", err)
 	}
 
@@ -1,6 +1,9 @@
 package git
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestCommitAndPushStagesAndCommitsOnlyTargetFile(t *testing.T) {
 	t.Parallel()
@@ -42,3 +45,27 @@ func TestCommitAndPushStagesAndCommitsOnlyTargetFile(t *testing.T) {
 		}
 	}
 }
+
+func TestCommitAndPushIncludesExtraPaths(t *testing.T) {
+	originalRun := run
+	defer func() { run = originalRun }()
+
+	var calls []string
+	run = func(dir string, name string, args ...string) error {
+		calls = append(calls, strings.Join(append([]string{name}, args...), " "))
+		return nil
+	}
+
+	if err := CommitAndPush("/repo", "2026/0404.md", "2026-04-04", "2026/0404"); err != nil {
+		t.Fatalf("CommitAndPush() error = %v", err)
+	}
+
+	want := []string{
+		"git add -- 2026/0404.md 2026/0404",
+		"git commit -m diary: 2026-04-04 --only -- 2026/0404.md 2026/0404",
+		"git push",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+}
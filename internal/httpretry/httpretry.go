@@ -0,0 +1,198 @@
+// Package httpretry retries HTTP requests that failed for transient reasons
+// and classifies the failures that remain.
+package httpretry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxErrorBodyBytes caps how much of an error response is kept in StatusError.
+const maxErrorBodyBytes = 64 << 10
+
+var (
+	// ErrUnauthorized matches errors for rejected credentials (401, 403).
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrUnavailable matches errors that were still transient when the
+	// retries ran out: rate limits, server errors and network failures.
+	ErrUnavailable = errors.New("service unavailable")
+)
+
+// Policy controls how often and how long Do retries.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; it doubles on each retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between attempts. A Retry-After longer than
+	// this ends the retries instead.
+	MaxDelay time.Duration
+	// RateLimitOnly retries 429 responses only. Use it for requests that must
+	// not be repeated when it is unclear whether the server acted on them,
+	// such as a POST that fails with a network error or 5xx.
+	RateLimitOnly bool
+}
+
+// DefaultPolicy suits API calls made by an unattended job.
+var DefaultPolicy = Policy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+}
+
+// StatusError is returned for a non-2xx response.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	// RetryAfter is the wait requested by the server, if any.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d - %s", e.StatusCode, e.Body)
+}
+
+// Is matches ErrUnauthorized and ErrUnavailable by status code.
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrUnavailable:
+		return retryableStatus(e.StatusCode)
+	}
+	return false
+}
+
+// Do sends req with client and retries network errors, 408, 429 and 5xx
+// responses (only 429 with RateLimitOnly) with exponential backoff, honoring
+// Retry-After. The request body
+// must be replayable (http.NewRequest sets GetBody for in-memory bodies).
+// Only 2xx responses are returned; anything else becomes a *StatusError.
+func (p Policy) Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attempts := max(p.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			err = fmt.Errorf("%w: %w", ErrUnavailable, err)
+			if p.RateLimitOnly {
+				return nil, err
+			}
+		} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			err = newStatusError(resp)
+		} else {
+			return resp, nil
+		}
+
+		wait := p.backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			if !p.retries(statusErr.StatusCode) {
+				return nil, err
+			}
+			if statusErr.RetryAfter > 0 {
+				wait = statusErr.RetryAfter
+			}
+		}
+		if attempt >= attempts || wait > p.MaxDelay {
+			return nil, err
+		}
+
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff returns the jittered wait before retry number attempt.
+func (p Policy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retries reports whether p retries a response with the given status code.
+func (p Policy) retries(code int) bool {
+	if p.RateLimitOnly {
+		return code == http.StatusTooManyRequests
+	}
+	return retryableStatus(code)
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+func newStatusError(resp *http.Response) *StatusError {
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
+
+// rewind returns a copy of req with a fresh body for the next attempt.
+func rewind(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("cannot retry %s %s: request body is not replayable", req.Method, req.URL)
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+// sleep waits for d or until ctx is done. It is a variable so tests can skip the wait.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,195 @@
+package httpretry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoRetriesTransientFailures(t *testing.T) {
+	waits := stubSleep(t)
+
+	var bodies []string
+	statuses := []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		resp := response(statuses[len(bodies)-1], "")
+		if len(bodies) == 2 {
+			resp.Header.Set("Retry-After", "7")
+		}
+		return resp, nil
+	})}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example", strings.NewReader(`{"a":1}`))
+	resp, err := DefaultPolicy.Do(client, req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if len(bodies) != 3 || bodies[2] != `{"a":1}` {
+		t.Fatalf("bodies = %q", bodies)
+	}
+	if len(*waits) != 2 || (*waits)[0] < 500*time.Millisecond || (*waits)[0] > time.Second || (*waits)[1] != 7*time.Second {
+		t.Fatalf("waits = %v", *waits)
+	}
+}
+
+func TestDoDoesNotRetryClientErrors(t *testing.T) {
+	stubSleep(t)
+
+	calls := 0
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return response(http.StatusUnauthorized, "invalid token"), nil
+	})}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example", nil)
+	_, err := DefaultPolicy.Do(client, req)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized || statusErr.Body != "invalid token" {
+		t.Fatalf("err = %v", err)
+	}
+	if !errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnauthorized only", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestDoGivesUpOnUnavailable(t *testing.T) {
+	waits := stubSleep(t)
+
+	calls := 0
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("connection refused")
+	})}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example", nil)
+	_, err := DefaultPolicy.Do(client, req)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if calls != DefaultPolicy.MaxAttempts || len(*waits) != DefaultPolicy.MaxAttempts-1 {
+		t.Fatalf("calls = %d, waits = %v", calls, *waits)
+	}
+}
+
+func TestDoStopsWhenRetryAfterIsTooLong(t *testing.T) {
+	waits := stubSleep(t)
+
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		resp := response(http.StatusTooManyRequests, "slow down")
+		resp.Header.Set("Retry-After", "3600")
+		return resp, nil
+	})}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example", nil)
+	_, err := DefaultPolicy.Do(client, req)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.RetryAfter != time.Hour {
+		t.Fatalf("err = %v", err)
+	}
+	if len(*waits) != 0 {
+		t.Fatalf("waits = %v", *waits)
+	}
+}
+
+func TestDoRateLimitOnly(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []int
+		netErr    bool
+		wantCalls int
+	}{
+		{name: "network error", netErr: true, wantCalls: 1},
+		{name: "server error", responses: []int{http.StatusBadGateway}, wantCalls: 1},
+		{name: "rate limited", responses: []int{http.StatusTooManyRequests, http.StatusOK}, wantCalls: 2},
+	}
+	policy := DefaultPolicy
+	policy.RateLimitOnly = true
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubSleep(t)
+
+			calls := 0
+			client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				calls++
+				if tt.netErr {
+					return nil, errors.New("connection reset")
+				}
+				return response(tt.responses[calls-1], ""), nil
+			})}
+
+			req, _ := http.NewRequest(http.MethodPost, "https://api.example", strings.NewReader(`{"a":1}`))
+			resp, err := policy.Do(client, req)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if wantErr := tt.responses == nil || tt.responses[len(tt.responses)-1] != http.StatusOK; (err != nil) != wantErr {
+				t.Fatalf("err = %v, want error %v", err, wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrUnavailable) {
+				t.Fatalf("err = %v, want ErrUnavailable", err)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 4, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]time.Duration{
+		"":                              0,
+		"30":                            30 * time.Second,
+		"-5":                            0,
+		"Fri, 03 Apr 2026 12:01:00 GMT": time.Minute,
+		"soon":                          0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+// stubSleep records the waits instead of sleeping.
+func stubSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	orig := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	t.Cleanup(func() { sleep = orig })
+	return &waits
+}
+
+func response(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,209 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	Japanese = "ja"
+	English  = "en"
+
+	DefaultLanguage = Japanese
+)
+
+// Messages holds every user-visible string that depends on diary.language.
+// Fields ending in a format verb are used with fmt.Sprintf.
+type Messages struct {
+	Language string
+
+	// Time-of-day names used for the default note groups.
+	EarlyMorning string
+	Morning      string
+	Afternoon    string
+	Evening      string
+	Night        string
+
+	// Heading for a per-channel note group; %s is the channel name.
+	ChannelSection string
+	// Heading for the group of renoted and quoted notes.
+	SharedSection string
+
+	// Attachment kinds in "[画像: alt text]" lines.
+	AttachmentImage     string
+	AttachmentVideo     string
+	AttachmentAudio     string
+	AttachmentFile      string
+	AttachmentSensitive string
+
+	// Markdown and text output.
+	SummaryHeading    string
+	GalleryHeading    string
+	SourcesHeading    string
+	DiaryCategory     string
+	WeeklyHeading     string
+	WeeklyCategory    string
+	MonthlyHeading    string
+	MonthlyCategory   string
+	SummaryTextHeader string
+	NoteCountLabel    string
+	TitleLabel        string
+	NoNotesSummary    string
+
+	// Discord embed.
+	DiscordTitle string
+
+	// CLI progress and status messages.
+	TargetWindow    string
+	FetchedNotes    string
+	ChunkedSummary  string
+	ProviderFailed  string
+	GeneratedBy     string
+	Saved           string
+	DiarySkipped    string
+	DiscordFailed   string
+	DiscordPosted   string
+	BackfillResult  string
+	BackfillExists  string
+	BackfillTotals  string
+	BackfillPending string
+	DigestLoaded    string
+	SyncDone        string
+	PushStart       string
+	PushDone        string
+}
+
+var catalog = map[string]*Messages{
+	Japanese: {
+		Language: Japanese,
+
+		EarlyMorning: "早朝",
+		Morning:      "午前",
+		Afternoon:    "午後",
+		Evening:      "夕方",
+		Night:        "夜",
+
+		ChannelSection: "#%s チャンネル",
+		SharedSection:  "シェアしたノート (Renote・引用)",
+
+		AttachmentImage:     "画像",
+		AttachmentVideo:     "動画",
+		AttachmentAudio:     "音声",
+		AttachmentFile:      "ファイル",
+		AttachmentSensitive: "閲覧注意",
+
+		SummaryHeading:    "Misskeyサマリー",
+		GalleryHeading:    "写真",
+		SourcesHeading:    "元のノート",
+		DiaryCategory:     "日記",
+		WeeklyHeading:     "週間サマリー",
+		WeeklyCategory:    "週報",
+		MonthlyHeading:    "月間サマリー",
+		MonthlyCategory:   "月報",
+		SummaryTextHeader: "%s のサマリー",
+		NoteCountLabel:    "ノート数",
+		TitleLabel:        "タイトル",
+		NoNotesSummary:    "この日は対象期間内のノートがありませんでした。",
+
+		DiscordTitle: "%s のMisskeyサマリー",
+
+		TargetWindow:    "%s の対象期間: %s 〜 %s",
+		FetchedNotes:    "Misskeyから%d件のノートを取得しました",
+		ChunkedSummary:  "プロンプトが長いため (推定 %d トークン)、%d 個に分けて要約します",
+		ProviderFailed:  "%s が失敗したため %s で再試行します: %v",
+		GeneratedBy:     "%s で生成しました",
+		Saved:           "保存しました: %s",
+		DiarySkipped:    "既存の日記があるためスキップしました: %s",
+		DiscordFailed:   "Discord投稿に失敗しました: %v",
+		DiscordPosted:   "Discordへ投稿しました",
+		BackfillResult:  "backfill結果:",
+		BackfillExists:  " (既存)",
+		BackfillTotals:  "保存: %d, スキップ: %d, 失敗: %d",
+		BackfillPending: ", 未処理: %d (再実行で続きから生成します)",
+		DigestLoaded:    "%s の日記を%d件読み込みました",
+		SyncDone:        "%d件のノートを同期しました: %s",
+		PushStart:       "📤 %s の日記をpushします",
+		PushDone:        "✅ pushしました",
+	},
+	English: {
+		Language: English,
+
+		EarlyMorning: "Early morning",
+		Morning:      "Morning",
+		Afternoon:    "Afternoon",
+		Evening:      "Evening",
+		Night:        "Night",
+
+		ChannelSection: "#%s channel",
+		SharedSection:  "Shared notes (renotes and quotes)",
+
+		AttachmentImage:     "Image",
+		AttachmentVideo:     "Video",
+		AttachmentAudio:     "Audio",
+		AttachmentFile:      "File",
+		AttachmentSensitive: "sensitive",
+
+		SummaryHeading:    "Misskey Summary",
+		GalleryHeading:    "Photos",
+		SourcesHeading:    "Sources",
+		DiaryCategory:     "Diary",
+		WeeklyHeading:     "Weekly Summary",
+		WeeklyCategory:    "Weekly",
+		MonthlyHeading:    "Monthly Summary",
+		MonthlyCategory:   "Monthly",
+		SummaryTextHeader: "Summary for %s",
+		NoteCountLabel:    "Notes",
+		TitleLabel:        "Title",
+		NoNotesSummary:    "There were no notes in the target period on this day.",
+
+		DiscordTitle: "Misskey summary for %s",
+
+		TargetWindow:    "Target period for %s: %s - %s",
+		FetchedNotes:    "Fetched %d notes from Misskey",
+		ChunkedSummary:  "The prompt is too long (about %d tokens); summarizing it in %d parts",
+		ProviderFailed:  "%s failed; retrying with %s: %v",
+		GeneratedBy:     "Generated with %s",
+		Saved:           "Saved: %s",
+		DiarySkipped:    "Skipped existing diary: %s",
+		DiscordFailed:   "Failed to post to Discord: %v",
+		DiscordPosted:   "Posted to Discord",
+		BackfillResult:  "Backfill results:",
+		BackfillExists:  " (exists)",
+		BackfillTotals:  "saved: %d, skipped: %d, failed: %d",
+		BackfillPending: ", pending: %d (rerun to continue)",
+		DigestLoaded:    "Loaded %[2]d diaries for %[1]s",
+		SyncDone:        "Synced %d notes: %s",
+		PushStart:       "📤 Pushing diary for %s",
+		PushDone:        "✅ Pushed",
+	},
+}
+
+// For returns the messages for a language code. An empty code selects the default language.
+func For(language string) (*Messages, error) {
+	code := strings.ToLower(strings.TrimSpace(language))
+	if code == "" {
+		code = DefaultLanguage
+	}
+
+	msg, ok := catalog[code]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q (supported: %s)", language, strings.Join(Languages(), ", "))
+	}
+	return msg, nil
+}
+
+// Default returns the messages for the default language.
+func Default() *Messages {
+	return catalog[DefaultLanguage]
+}
+
+// Languages returns the supported language codes.
+func Languages() []string {
+	codes := make([]string, 0, len(catalog))
+	for code := range catalog {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,38 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFor(t *testing.T) {
+	msg, err := For("")
+	if err != nil || msg.Language != Japanese {
+		t.Fatalf("For(\"\") = %v, %v, want Japanese", msg, err)
+	}
+
+	msg, err = For(" EN ")
+	if err != nil || msg.Language != English {
+		t.Fatalf("For(\" EN \") = %v, %v, want English", msg, err)
+	}
+
+	if _, err := For("fr"); err == nil {
+		t.Fatal("For(\"fr\") error = nil, want unsupported language")
+	}
+}
+
+func TestCatalogIsComplete(t *testing.T) {
+	for _, code := range Languages() {
+		msg, err := For(code)
+		if err != nil {
+			t.Fatalf("For(%q) error = %v", code, err)
+		}
+
+		v := reflect.ValueOf(*msg)
+		for i := range v.NumField() {
+			if v.Field(i).Kind() == reflect.String && v.Field(i).String() == "" {
+				t.Errorf("%s: %s is empty", code, v.Type().Field(i).Name)
+			}
+		}
+	}
+}
@@ -2,12 +2,14 @@ package misskey
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
+	"github.com/soli0222/diary-cli/internal/httpretry"
 	"github.com/soli0222/diary-cli/internal/models"
 )
 
@@ -16,6 +18,8 @@ type Client struct {
 	BaseURL    string
 	Token      string
 	HTTPClient *http.Client
+	// Retry decides how transient failures are retried.
+	Retry httpretry.Policy
 }
 
 // NewClient creates a new Misskey client
@@ -26,22 +30,18 @@ func NewClient(baseURL, token string) *Client {
 		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		Retry: httpretry.DefaultPolicy,
 	}
 }
 
 // GetMe returns the authenticated user's information
-func (c *Client) GetMe() (*models.MeDetailed, error) {
-	resp, err := c.post("/api/i", nil)
+func (c *Client) GetMe(ctx context.Context) (*models.MeDetailed, error) {
+	resp, err := c.post(ctx, "/api/i", nil)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
-	}
-
 	var me models.MeDetailed
 	if err := json.NewDecoder(resp.Body).Decode(&me); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
@@ -64,18 +64,13 @@ type GetUserNotesRequest struct {
 }
 
 // GetUserNotes fetches notes for a specific user
-func (c *Client) GetUserNotes(req GetUserNotesRequest) ([]models.Note, error) {
-	resp, err := c.post("/api/users/notes", req)
+func (c *Client) GetUserNotes(ctx context.Context, req GetUserNotesRequest) ([]models.Note, error) {
+	resp, err := c.post(ctx, "/api/users/notes", req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
-	}
-
 	var notes []models.Note
 	if err := json.NewDecoder(resp.Body).Decode(&notes); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
@@ -85,7 +80,7 @@ func (c *Client) GetUserNotes(req GetUserNotesRequest) ([]models.Note, error) {
 }
 
 // GetNotesForDay fetches all notes for a specific day
-func (c *Client) GetNotesForDay(userID string, date time.Time, includeRenotes bool) ([]models.Note, error) {
+func (c *Client) GetNotesForDay(ctx context.Context, userID string, date time.Time, includeRenotes bool) ([]models.Note, error) {
 	// Get start and end of day in the local timezone
 	loc := date.Location()
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
@@ -112,7 +107,7 @@ func (c *Client) GetNotesForDay(userID string, date time.Time, includeRenotes bo
 			req.SinceID = lastID
 		}
 
-		notes, err := c.GetUserNotes(req)
+		notes, err := c.GetUserNotes(ctx, req)
 		if err != nil {
 			return nil, err
 		}
@@ -136,7 +131,7 @@ func (c *Client) GetNotesForDay(userID string, date time.Time, includeRenotes bo
 }
 
 // GetNotesForTimeRange fetches all notes for a specific time range
-func (c *Client) GetNotesForTimeRange(userID string, startTime, endTime time.Time, includeRenotes bool) ([]models.Note, error) {
+func (c *Client) GetNotesForTimeRange(ctx context.Context, userID string, startTime, endTime time.Time, includeRenotes bool) ([]models.Note, error) {
 	sinceMs := startTime.UnixMilli()
 	untilMs := endTime.UnixMilli()
 
@@ -158,7 +153,7 @@ func (c *Client) GetNotesForTimeRange(userID string, startTime, endTime time.Tim
 			req.SinceID = lastID
 		}
 
-		notes, err := c.GetUserNotes(req)
+		notes, err := c.GetUserNotes(ctx, req)
 		if err != nil {
 			return nil, err
 		}
@@ -181,8 +176,58 @@ func (c *Client) GetNotesForTimeRange(userID string, startTime, endTime time.Tim
 	return allNotes, nil
 }
 
-// post makes a POST request to the Misskey API
-func (c *Client) post(endpoint string, body interface{}) (*http.Response, error) {
+// ShowNote fetches a single note by ID
+func (c *Client) ShowNote(ctx context.Context, noteID string) (*models.Note, error) {
+	resp, err := c.post(ctx, "/api/notes/show", map[string]string{"noteId": noteID})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var note models.Note
+	if err := json.NewDecoder(resp.Body).Decode(&note); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &note, nil
+}
+
+// ShowDriveFile fetches drive file metadata by ID
+func (c *Client) ShowDriveFile(ctx context.Context, fileID string) (*models.DriveFile, error) {
+	resp, err := c.post(ctx, "/api/drive/files/show", map[string]string{"fileId": fileID})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var file models.DriveFile
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &file, nil
+}
+
+// ShowChannel fetches a channel by ID
+func (c *Client) ShowChannel(ctx context.Context, channelID string) (*models.Channel, error) {
+	resp, err := c.post(ctx, "/api/channels/show", map[string]string{"channelId": channelID})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var channel models.Channel
+	if err := json.NewDecoder(resp.Body).Decode(&channel); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &channel, nil
+}
+
+// post makes a POST request to the Misskey API, retrying transient failures.
+// Errors wrap *httpretry.StatusError for non-2xx responses, so callers can test
+// them against httpretry.ErrUnauthorized and httpretry.ErrUnavailable.
+func (c *Client) post(ctx context.Context, endpoint string, body interface{}) (*http.Response, error) {
 	var jsonBody []byte
 	var err error
 
@@ -195,7 +240,7 @@ func (c *Client) post(endpoint string, body interface{}) (*http.Response, error)
 		jsonBody = []byte("{}")
 	}
 
-	req, err := http.NewRequest("POST", c.BaseURL+endpoint, bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+endpoint, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -203,5 +248,13 @@ func (c *Client) post(endpoint string, body interface{}) (*http.Response, error)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.Token)
 
-	return c.HTTPClient.Do(req)
+	resp, err := c.Retry.Do(c.HTTPClient, req)
+	var statusErr *httpretry.StatusError
+	if errors.As(err, &statusErr) {
+		return nil, fmt.Errorf("API error: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return resp, nil
 }
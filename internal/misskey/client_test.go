@@ -2,11 +2,15 @@ package misskey
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"testing"
 	"time"
+
+	"github.com/soli0222/diary-cli/internal/httpretry"
 )
 
 func TestClientGetMe(t *testing.T) {
@@ -20,7 +24,7 @@ func TestClientGetMe(t *testing.T) {
 		}
 		return jsonResponse(http.StatusOK, `{"id":"user-1","username":"soli"}`), nil
 	})}
-	me, err := client.GetMe()
+	me, err := client.GetMe(context.Background())
 	if err != nil {
 		t.Fatalf("GetMe() error = %v", err)
 	}
@@ -38,12 +42,104 @@ func TestClientGetUserNotesAPIError(t *testing.T) {
 	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
 		return jsonResponse(http.StatusBadRequest, "bad request\n"), nil
 	})}
-	_, err := client.GetUserNotes(GetUserNotesRequest{UserID: "user-1"})
+	_, err := client.GetUserNotes(context.Background(), GetUserNotesRequest{UserID: "user-1"})
 	if err == nil || err.Error() != "API error: 400 - bad request\n" {
 		t.Fatalf("err = %v", err)
 	}
 }
 
+func TestClientGetMeRejectedToken(t *testing.T) {
+	client := NewClient("https://misskey.example", "revoked")
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusUnauthorized, `{"error":{"code":"AUTHENTICATION_FAILED"}}`), nil
+	})}
+	_, err := client.GetMe(context.Background())
+	if !errors.Is(err, httpretry.ErrUnauthorized) || errors.Is(err, httpretry.ErrUnavailable) {
+		t.Fatalf("err = %v, want httpretry.ErrUnauthorized", err)
+	}
+}
+
+func TestClientGetMeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	client := NewClient("https://misskey.example", "secret")
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		cancel()
+		return nil, r.Context().Err()
+	})}
+	_, err := client.GetMe(ctx)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, httpretry.ErrUnavailable) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestClientShowNote(t *testing.T) {
+	var gotBody map[string]any
+
+	client := NewClient("https://misskey.example", "secret")
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/api/notes/show" {
+			t.Fatalf("path = %q, want /api/notes/show", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		return jsonResponse(http.StatusOK, `{"id":"note-1","text":"parent","user":{"id":"u2","username":"alice"}}`), nil
+	})}
+
+	note, err := client.ShowNote(context.Background(), "note-1")
+	if err != nil {
+		t.Fatalf("ShowNote() error = %v", err)
+	}
+	if gotBody["noteId"] != "note-1" {
+		t.Fatalf("body = %#v", gotBody)
+	}
+	if note.ID != "note-1" || note.User == nil || note.User.Username != "alice" {
+		t.Fatalf("note = %#v", note)
+	}
+}
+
+func TestClientShowDriveFile(t *testing.T) {
+	client := NewClient("https://misskey.example", "secret")
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/api/drive/files/show" {
+			t.Fatalf("path = %q, want /api/drive/files/show", r.URL.Path)
+		}
+		return jsonResponse(http.StatusOK, `{"id":"f1","name":"lunch.jpg","type":"image/jpeg","comment":"ラーメン","isSensitive":false,"url":"https://files.example/f1.jpg","thumbnailUrl":"https://files.example/thumb-f1.webp"}`), nil
+	})}
+
+	file, err := client.ShowDriveFile(context.Background(), "f1")
+	if err != nil {
+		t.Fatalf("ShowDriveFile() error = %v", err)
+	}
+	if file.Name != "lunch.jpg" || file.Type != "image/jpeg" || file.Comment == nil || *file.Comment != "ラーメン" || file.ThumbnailURL == nil {
+		t.Fatalf("file = %#v", file)
+	}
+}
+
+func TestClientShowChannel(t *testing.T) {
+	client := NewClient("https://misskey.example", "secret")
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/api/channels/show" {
+			t.Fatalf("path = %q, want /api/channels/show", r.URL.Path)
+		}
+		return jsonResponse(http.StatusOK, `{"id":"ch-1","name":"cooking"}`), nil
+	})}
+
+	channel, err := client.ShowChannel(context.Background(), "ch-1")
+	if err != nil {
+		t.Fatalf("ShowChannel() error = %v", err)
+	}
+	if channel.ID != "ch-1" || channel.Name != "cooking" {
+		t.Fatalf("channel = %#v", channel)
+	}
+}
+
 func TestClientGetNotesForTimeRangePaginates(t *testing.T) {
 	loc := time.FixedZone("JST", 9*60*60)
 	start := time.Date(2026, 2, 23, 5, 0, 0, 0, loc)
@@ -80,7 +176,7 @@ func TestClientGetNotesForTimeRangePaginates(t *testing.T) {
 		}
 		return nil, nil
 	})}
-	notes, err := client.GetNotesForTimeRange("user-1", start, end, true)
+	notes, err := client.GetNotesForTimeRange(context.Background(), "user-1", start, end, true)
 	if err != nil {
 		t.Fatalf("GetNotesForTimeRange() error = %v", err)
 	}
@@ -0,0 +1,49 @@
+package misskey
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+// ReadNotesExport decodes the JSON produced by Settings → Export notes.
+// The export omits user and renote bodies, so those fields stay empty.
+func ReadNotesExport(r io.Reader) ([]models.Note, error) {
+	var notes []models.Note
+	if err := json.NewDecoder(r).Decode(&notes); err != nil {
+		return nil, fmt.Errorf("failed to decode notes export: %w", err)
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].CreatedAt.Before(notes[j].CreatedAt)
+	})
+	return notes, nil
+}
+
+// LoadNotesExport reads a notes export file from disk.
+func LoadNotesExport(path string) ([]models.Note, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open notes export: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return ReadNotesExport(f)
+}
+
+// NotesInRange returns the notes created within [startTime, endTime).
+func NotesInRange(notes []models.Note, startTime, endTime time.Time) []models.Note {
+	var result []models.Note
+	for _, note := range notes {
+		if note.CreatedAt.Before(startTime) || !note.CreatedAt.Before(endTime) {
+			continue
+		}
+		result = append(result, note)
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package misskey
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadNotesExport(t *testing.T) {
+	export := `[
+  {"id":"b","text":"later","createdAt":"2026-02-23T12:00:00.000Z","fileIds":["f1"],"replyId":null,"renoteId":null,"poll":null,"cw":"spoiler","visibility":"home","visibleUserIds":[],"localOnly":true},
+  {"id":"a","text":"earlier","createdAt":"2026-02-22T21:00:00.000Z","fileIds":[],"replyId":"r1","renoteId":null,"poll":null,"cw":null,"visibility":"public","visibleUserIds":[],"localOnly":false},
+  {"id":"c","text":null,"createdAt":"2026-02-24T00:00:00.000Z","fileIds":[],"replyId":null,"renoteId":"rn1","poll":null,"cw":null,"visibility":"public","visibleUserIds":[],"localOnly":false}
+]`
+
+	notes, err := ReadNotesExport(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ReadNotesExport() error = %v", err)
+	}
+	if len(notes) != 3 {
+		t.Fatalf("len(notes) = %d, want 3", len(notes))
+	}
+	if notes[0].ID != "a" || notes[1].ID != "b" {
+		t.Fatalf("notes should be sorted chronologically: %#v", notes)
+	}
+	if notes[0].ReplyID == nil || *notes[0].ReplyID != "r1" {
+		t.Fatalf("ReplyID = %v", notes[0].ReplyID)
+	}
+	if notes[1].CW == nil || *notes[1].CW != "spoiler" || notes[1].Visibility != "home" || !notes[1].LocalOnly {
+		t.Fatalf("unexpected note fields: %#v", notes[1])
+	}
+	if notes[2].IsOriginalNote() {
+		t.Fatal("pure renote from export should not be an original note")
+	}
+
+	start := time.Date(2026, 2, 22, 20, 0, 0, 0, time.UTC)
+	inRange := NotesInRange(notes, start, start.Add(17*time.Hour))
+	if len(inRange) != 2 {
+		t.Fatalf("len(NotesInRange()) = %d, want 2", len(inRange))
+	}
+
+	if _, err := ReadNotesExport(strings.NewReader(`{"not":"an array"}`)); err == nil {
+		t.Fatal("expected error for malformed export")
+	}
+}
@@ -4,22 +4,23 @@ import "time"
 
 // Note represents a Misskey note
 type Note struct {
-	ID         string     `json:"id"`
-	CreatedAt  time.Time  `json:"createdAt"`
-	Text       *string    `json:"text"`
-	CW         *string    `json:"cw"`
-	UserID     string     `json:"userId"`
-	User       *UserLite  `json:"user,omitempty"`
-	ReplyID    *string    `json:"replyId"`
-	RenoteID   *string    `json:"renoteId"`
-	Renote     *Note      `json:"renote,omitempty"`
-	Reply      *Note      `json:"reply,omitempty"`
-	Visibility string     `json:"visibility"`
-	LocalOnly  bool       `json:"localOnly"`
-	IsHidden   bool       `json:"isHidden"`
-	Tags       []string   `json:"tags"`
-	FileIDs    []string   `json:"fileIds"`
-	ChannelID  *string    `json:"channelId"`
+	ID         string      `json:"id"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	Text       *string     `json:"text"`
+	CW         *string     `json:"cw"`
+	UserID     string      `json:"userId"`
+	User       *UserLite   `json:"user,omitempty"`
+	ReplyID    *string     `json:"replyId"`
+	RenoteID   *string     `json:"renoteId"`
+	Renote     *Note       `json:"renote,omitempty"`
+	Reply      *Note       `json:"reply,omitempty"`
+	Visibility string      `json:"visibility"`
+	LocalOnly  bool        `json:"localOnly"`
+	IsHidden   bool        `json:"isHidden"`
+	Tags       []string    `json:"tags"`
+	FileIDs    []string    `json:"fileIds"`
+	Files      []DriveFile `json:"files,omitempty"`
+	ChannelID  *string     `json:"channelId"`
 }
 
 // UserLite represents a minimal Misskey user
@@ -30,6 +31,23 @@ type UserLite struct {
 	Host     *string `json:"host"`
 }
 
+// DriveFile represents a Misskey drive file attached to a note
+type DriveFile struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	Type         string  `json:"type"`
+	Comment      *string `json:"comment"`
+	IsSensitive  bool    `json:"isSensitive"`
+	URL          string  `json:"url"`
+	ThumbnailURL *string `json:"thumbnailUrl"`
+}
+
+// Channel represents a Misskey channel
+type Channel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 // MeDetailed represents the authenticated user's detailed info
 type MeDetailed struct {
 	ID       string  `json:"id"`
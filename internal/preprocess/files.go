@@ -0,0 +1,74 @@
+package preprocess
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+// maxAttachmentRunes caps the alt text quoted for one attachment.
+const maxAttachmentRunes = 120
+
+// AttachmentLabels names the attachment kinds used in attachment lines.
+type AttachmentLabels struct {
+	Image     string
+	Video     string
+	Audio     string
+	File      string
+	Sensitive string
+}
+
+// EnrichNotesWithFiles appends one "[画像: alt text]" style line per attached
+// file to the note text, so that notes with only attachments are not empty.
+// Files must already be resolved into Note.Files.
+func EnrichNotesWithFiles(notes []models.Note, labels AttachmentLabels) []models.Note {
+	enriched := make([]models.Note, len(notes))
+	copy(enriched, notes)
+
+	for i := range enriched {
+		if len(enriched[i].Files) == 0 || !enriched[i].IsOriginalNote() {
+			continue
+		}
+
+		lines := make([]string, 0, len(enriched[i].Files))
+		for _, f := range enriched[i].Files {
+			lines = append(lines, attachmentLine(f, labels))
+		}
+
+		text := strings.Join(lines, "\n")
+		if enriched[i].Text != nil && strings.TrimSpace(*enriched[i].Text) != "" {
+			text = *enriched[i].Text + "\n" + text
+		}
+		enriched[i].Text = &text
+	}
+
+	return enriched
+}
+
+func attachmentLine(f models.DriveFile, labels AttachmentLabels) string {
+	kind := labels.File
+	switch {
+	case strings.HasPrefix(f.Type, "image/"):
+		kind = labels.Image
+	case strings.HasPrefix(f.Type, "video/"):
+		kind = labels.Video
+	case strings.HasPrefix(f.Type, "audio/"):
+		kind = labels.Audio
+	}
+	if f.IsSensitive {
+		kind = fmt.Sprintf("%s (%s)", kind, labels.Sensitive)
+	}
+
+	desc := ""
+	if f.Comment != nil {
+		desc = strings.Join(strings.Fields(*f.Comment), " ")
+	}
+	if desc == "" {
+		desc = f.Name
+	}
+	if desc == "" {
+		return "[" + kind + "]"
+	}
+	return "[" + kind + ": " + truncate(desc, maxAttachmentRunes) + "]"
+}
@@ -0,0 +1,36 @@
+package preprocess
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+func TestEnrichNotesWithFiles(t *testing.T) {
+	labels := AttachmentLabels{Image: "画像", Video: "動画", Audio: "音声", File: "ファイル", Sensitive: "閲覧注意"}
+	base := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
+
+	photoOnly := models.Note{ID: "photo", CreatedAt: base, Files: []models.DriveFile{
+		{Name: "IMG_0001.jpg", Type: "image/jpeg", Comment: strPtr("夕焼けの\n川沿い")},
+		{Name: "clip.mp4", Type: "video/mp4", IsSensitive: true},
+	}}
+	withText := makeNote("text", "今日のお昼", base)
+	withText.Files = []models.DriveFile{{Name: "menu.pdf", Type: "application/pdf"}}
+	plain := makeNote("plain", "plain", base)
+
+	got := EnrichNotesWithFiles([]models.Note{photoOnly, withText, plain}, labels)
+
+	if want := "[画像: 夕焼けの 川沿い]\n[動画 (閲覧注意): clip.mp4]"; got[0].Text == nil || *got[0].Text != want {
+		t.Errorf("photo-only text = %v, want %q", got[0].Text, want)
+	}
+	if want := "今日のお昼\n[ファイル: menu.pdf]"; *got[1].Text != want {
+		t.Errorf("text = %q, want %q", *got[1].Text, want)
+	}
+	if *got[2].Text != "plain" {
+		t.Errorf("plain text changed: %q", *got[2].Text)
+	}
+	if photoOnly.Text != nil || *withText.Text != "今日のお昼" {
+		t.Error("EnrichNotesWithFiles() must not modify its input")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/soli0222/diary-cli/internal/i18n"
 	"github.com/soli0222/diary-cli/internal/models"
 )
 
@@ -15,9 +16,79 @@ type TimeGroup struct {
 	Notes []models.Note
 }
 
-// GroupNotes sorts notes chronologically and groups them by time period.
+// TimeBucket is a named time-of-day range [Start, End) in hours.
+// A bucket whose End is not after Start wraps past midnight.
+type TimeBucket struct {
+	Label string
+	Start int
+	End   int
+}
+
+// Contains reports whether the given hour falls within the bucket.
+func (b TimeBucket) Contains(hour int) bool {
+	if b.Start < b.End {
+		return hour >= b.Start && hour < b.End
+	}
+	return hour >= b.Start || hour < b.End
+}
+
+// DefaultTimeBuckets returns the five standard time periods labelled in the given language.
+func DefaultTimeBuckets(msg *i18n.Messages) []TimeBucket {
+	return DefaultTimeBucketsFrom(msg, 5)
+}
+
+// DefaultTimeBucketsFrom returns the standard time periods in chronological
+// order for a day that starts at startHour. When startHour falls inside a
+// period, that period is split there and its earlier part comes last.
+func DefaultTimeBucketsFrom(msg *i18n.Messages, startHour int) []TimeBucket {
+	if msg == nil {
+		msg = i18n.Default()
+	}
+	periods := []struct {
+		name       string
+		start, end int
+	}{
+		{msg.EarlyMorning, 5, 9},
+		{msg.Morning, 9, 12},
+		{msg.Afternoon, 12, 17},
+		{msg.Evening, 17, 21},
+		{msg.Night, 21, 5},
+	}
+	bucket := func(i, start, end int) TimeBucket {
+		return TimeBucket{Label: fmt.Sprintf("%s (%d:00-%d:00)", periods[i].name, start, end), Start: start, End: end}
+	}
+
+	first := 0
+	for i, p := range periods {
+		if (TimeBucket{Start: p.start, End: p.end}).Contains(startHour) {
+			first = i
+		}
+	}
+
+	p := periods[first]
+	buckets := []TimeBucket{bucket(first, startHour, p.end)}
+	for i := 1; i < len(periods); i++ {
+		j := (first + i) % len(periods)
+		buckets = append(buckets, bucket(j, periods[j].start, periods[j].end))
+	}
+	if startHour != p.start {
+		buckets = append(buckets, bucket(first, p.start, startHour))
+	}
+	return buckets
+}
+
+// GroupNotes sorts notes chronologically and groups them by the default time periods.
 func GroupNotes(notes []models.Note, loc *time.Location) []TimeGroup {
+	return GroupNotesByBuckets(notes, loc, DefaultTimeBuckets(nil))
+}
+
+// GroupNotesByBuckets sorts notes chronologically and groups them into the given buckets.
+// Notes whose hour matches no bucket fall into the last one.
+func GroupNotesByBuckets(notes []models.Note, loc *time.Location, buckets []TimeBucket) []TimeGroup {
 	loc = normalizeLocation(loc)
+	if len(buckets) == 0 {
+		buckets = DefaultTimeBuckets(nil)
+	}
 
 	// Filter to original notes only (no pure renotes)
 	var filtered []models.Note
@@ -33,62 +104,87 @@ func GroupNotes(notes []models.Note, loc *time.Location) []TimeGroup {
 	})
 
 	// Group by time period
-	groups := map[string][]models.Note{
-		"早朝 (5:00-9:00)":   {},
-		"午前 (9:00-12:00)":  {},
-		"午後 (12:00-17:00)": {},
-		"夕方 (17:00-21:00)": {},
-		"夜 (21:00-5:00)":   {},
-	}
-	order := []string{
-		"早朝 (5:00-9:00)",
-		"午前 (9:00-12:00)",
-		"午後 (12:00-17:00)",
-		"夕方 (17:00-21:00)",
-		"夜 (21:00-5:00)",
-	}
-
+	grouped := make([][]models.Note, len(buckets))
 	for _, n := range filtered {
 		hour := n.CreatedAt.In(loc).Hour()
-		var label string
-		switch {
-		case hour >= 5 && hour < 9:
-			label = order[0]
-		case hour >= 9 && hour < 12:
-			label = order[1]
-		case hour >= 12 && hour < 17:
-			label = order[2]
-		case hour >= 17 && hour < 21:
-			label = order[3]
-		default:
-			label = order[4]
+		idx := len(buckets) - 1
+		for i, b := range buckets {
+			if b.Contains(hour) {
+				idx = i
+				break
+			}
 		}
-		groups[label] = append(groups[label], n)
+		grouped[idx] = append(grouped[idx], n)
 	}
 
 	var result []TimeGroup
-	for _, label := range order {
-		if len(groups[label]) > 0 {
-			result = append(result, TimeGroup{Label: label, Notes: groups[label]})
+	for i, b := range buckets {
+		if len(grouped[i]) > 0 {
+			result = append(result, TimeGroup{Label: b.Label, Notes: grouped[i]})
 		}
 	}
 	return result
 }
 
+// Channel is a Misskey channel whose notes get their own section.
+type Channel struct {
+	ID    string
+	Label string
+}
+
+// SplitByChannel separates notes posted to the given channels. It returns the
+// remaining notes and one chronologically sorted group per channel that has
+// notes, in the order the channels are given.
+func SplitByChannel(notes []models.Note, channels []Channel) ([]models.Note, []TimeGroup) {
+	index := make(map[string]int, len(channels))
+	for i, c := range channels {
+		index[c.ID] = i
+	}
+
+	var rest []models.Note
+	byChannel := make([][]models.Note, len(channels))
+	for _, n := range notes {
+		if n.ChannelID == nil {
+			rest = append(rest, n)
+			continue
+		}
+		if i, ok := index[*n.ChannelID]; ok {
+			byChannel[i] = append(byChannel[i], n)
+		}
+	}
+
+	var groups []TimeGroup
+	for i, c := range channels {
+		if len(byChannel[i]) == 0 {
+			continue
+		}
+		sort.Slice(byChannel[i], func(a, b int) bool {
+			return byChannel[i][a].CreatedAt.Before(byChannel[i][b].CreatedAt)
+		})
+		groups = append(groups, TimeGroup{Label: c.Label, Notes: byChannel[i]})
+	}
+	return rest, groups
+}
+
 // FormatGroupedNotes formats grouped notes into a human-readable string for Claude.
 func FormatGroupedNotes(groups []TimeGroup, loc *time.Location) string {
+	return formatGroupedNotes(groups, loc, false)
+}
+
+// FormatGroupedNotesWithCitations is like FormatGroupedNotes but tags each
+// note with "[note:ID]" so the summary can cite it.
+func FormatGroupedNotesWithCitations(groups []TimeGroup, loc *time.Location) string {
+	return formatGroupedNotes(groups, loc, true)
+}
+
+func formatGroupedNotes(groups []TimeGroup, loc *time.Location, cite bool) string {
 	loc = normalizeLocation(loc)
 
 	var sb strings.Builder
 	for _, g := range groups {
 		fmt.Fprintf(&sb, "## %s\n", g.Label)
 		for _, n := range g.Notes {
-			ts := n.CreatedAt.In(loc).Format("15:04")
-			text := n.GetDisplayText()
-			if text == "" {
-				continue
-			}
-			fmt.Fprintf(&sb, "- [%s] %s\n", ts, text)
+			writeNoteLine(&sb, n, loc, cite)
 		}
 		sb.WriteString("\n")
 	}
@@ -112,16 +208,97 @@ func FormatAllNotes(notes []models.Note, loc *time.Location) string {
 
 	var sb strings.Builder
 	for _, n := range filtered {
-		ts := n.CreatedAt.In(loc).Format("15:04")
-		text := n.GetDisplayText()
-		if text == "" {
-			continue
-		}
-		fmt.Fprintf(&sb, "- [%s] %s\n", ts, text)
+		writeNoteLine(&sb, n, loc, false)
 	}
 	return sb.String()
 }
 
+// maxContextRunes caps how much of a replied-to or renoted note is quoted.
+const maxContextRunes = 200
+
+// SplitShared separates pure renotes and quotes from the other notes and
+// returns them as one chronologically sorted group labelled label.
+func SplitShared(notes []models.Note, label string) ([]models.Note, []TimeGroup) {
+	var rest, shared []models.Note
+	for _, n := range notes {
+		if n.Renote != nil || n.RenoteID != nil {
+			shared = append(shared, n)
+		} else {
+			rest = append(rest, n)
+		}
+	}
+	if len(shared) == 0 {
+		return rest, nil
+	}
+
+	sort.Slice(shared, func(i, j int) bool {
+		return shared[i].CreatedAt.Before(shared[j].CreatedAt)
+	})
+	return rest, []TimeGroup{{Label: label, Notes: shared}}
+}
+
+// writeNoteLine writes a note as a list item. Pure renotes are attributed to
+// the original author; replies and quotes are followed by an indented excerpt
+// of the note they respond to. With cite, the time is followed by "[note:ID]".
+func writeNoteLine(sb *strings.Builder, n models.Note, loc *time.Location, cite bool) {
+	prefix := "[" + n.CreatedAt.In(loc).Format("15:04") + "]"
+	if cite {
+		prefix += " [note:" + n.ID + "]"
+	}
+
+	if !n.IsOriginalNote() {
+		if n.Renote == nil {
+			return
+		}
+		if line := contextLine("RN", n.Renote); line != "" {
+			fmt.Fprintf(sb, "- %s %s\n", prefix, line)
+		}
+		return
+	}
+
+	text := n.GetDisplayText()
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(sb, "- %s %s\n", prefix, text)
+
+	if n.Reply != nil {
+		if line := contextLine("Re", n.Reply); line != "" {
+			fmt.Fprintf(sb, "  > %s\n", line)
+		}
+	}
+	if n.Renote != nil {
+		if line := contextLine("QT", n.Renote); line != "" {
+			fmt.Fprintf(sb, "  > %s\n", line)
+		}
+	}
+}
+
+// contextLine formats another user's note as "[tag @user] excerpt".
+func contextLine(tag string, n *models.Note) string {
+	excerpt := strings.Join(strings.Fields(n.GetDisplayText()), " ")
+	if excerpt == "" {
+		return ""
+	}
+	if r := []rune(excerpt); len(r) > maxContextRunes {
+		excerpt = string(r[:maxContextRunes]) + "…"
+	}
+	if handle := userHandle(n.User); handle != "" {
+		return fmt.Sprintf("[%s %s] %s", tag, handle, excerpt)
+	}
+	return fmt.Sprintf("[%s] %s", tag, excerpt)
+}
+
+func userHandle(u *models.UserLite) string {
+	if u == nil || u.Username == "" {
+		return ""
+	}
+	if u.Host != nil && *u.Host != "" {
+		return "@" + u.Username + "@" + *u.Host
+	}
+	return "@" + u.Username
+}
+
 func normalizeLocation(loc *time.Location) *time.Location {
 	if loc != nil {
 		return loc
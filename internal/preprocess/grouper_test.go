@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/soli0222/diary-cli/internal/i18n"
 	"github.com/soli0222/diary-cli/internal/models"
 )
 
@@ -68,6 +69,76 @@ func TestGroupNotes_TimeGroupingInJST(t *testing.T) {
 	}
 }
 
+func TestGroupNotesByBuckets_EnglishLabels(t *testing.T) {
+	notes := []models.Note{
+		makeNote("1", "morning note", time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)), // JST 09:00
+		makeNote("2", "late night", time.Date(2026, 2, 15, 18, 0, 0, 0, time.UTC)),  // JST 03:00
+	}
+
+	msg, err := i18n.For(i18n.English)
+	if err != nil {
+		t.Fatalf("i18n.For() error = %v", err)
+	}
+	groups := GroupNotesByBuckets(notes, tokyo, DefaultTimeBuckets(msg))
+
+	if len(groups) != 2 {
+		t.Fatalf("GroupNotesByBuckets() returned %d groups, want 2", len(groups))
+	}
+	if groups[0].Label != "Morning (9:00-12:00)" || groups[1].Label != "Night (21:00-5:00)" {
+		t.Fatalf("labels = %q, %q", groups[0].Label, groups[1].Label)
+	}
+}
+
+func TestGroupNotesByBuckets_WrappingBuckets(t *testing.T) {
+	buckets := []TimeBucket{
+		{Label: "勤務中", Start: 18, End: 6},
+		{Label: "帰宅後", Start: 6, End: 12},
+	}
+	notes := []models.Note{
+		makeNote("1", "on shift", time.Date(2026, 2, 15, 14, 0, 0, 0, time.UTC)),    // JST 23:00
+		makeNote("2", "after shift", time.Date(2026, 2, 15, 22, 0, 0, 0, time.UTC)), // JST 07:00
+		makeNote("3", "unmatched", time.Date(2026, 2, 16, 5, 0, 0, 0, time.UTC)),    // JST 14:00
+	}
+
+	groups := GroupNotesByBuckets(notes, tokyo, buckets)
+
+	if len(groups) != 2 {
+		t.Fatalf("GroupNotesByBuckets() returned %d groups, want 2", len(groups))
+	}
+	if groups[0].Label != "勤務中" || len(groups[0].Notes) != 1 {
+		t.Fatalf("groups[0] = %#v", groups[0])
+	}
+	if groups[1].Label != "帰宅後" || len(groups[1].Notes) != 2 {
+		t.Fatalf("unmatched notes should fall into the last bucket: %#v", groups[1])
+	}
+}
+
+func TestGroupNotesByBuckets_DayStartInsideBucket(t *testing.T) {
+	// Day starts at JST 18:00: the 17:xx note belongs to the next calendar
+	// day and must come last, after the night and morning notes.
+	notes := []models.Note{
+		makeNote("1", "start", time.Date(2026, 2, 20, 9, 30, 0, 0, time.UTC)),  // JST 2/20 18:30
+		makeNote("2", "night", time.Date(2026, 2, 20, 14, 0, 0, 0, time.UTC)),  // JST 2/20 23:00
+		makeNote("3", "morning", time.Date(2026, 2, 21, 1, 0, 0, 0, time.UTC)), // JST 2/21 10:00
+		makeNote("4", "end", time.Date(2026, 2, 21, 8, 30, 0, 0, time.UTC)),    // JST 2/21 17:30
+	}
+
+	groups := GroupNotesByBuckets(notes, tokyo, DefaultTimeBucketsFrom(nil, 18))
+
+	var ids []string
+	for _, g := range groups {
+		for _, n := range g.Notes {
+			ids = append(ids, n.ID)
+		}
+	}
+	if strings.Join(ids, ",") != "1,2,3,4" {
+		t.Fatalf("note order = %v, groups = %#v", ids, groups)
+	}
+	if groups[0].Label != "夕方 (18:00-21:00)" || groups[len(groups)-1].Label != "夕方 (17:00-18:00)" {
+		t.Fatalf("groups = %#v", groups)
+	}
+}
+
 func TestGroupNotes_FiltersPureRenotes(t *testing.T) {
 	notes := []models.Note{
 		makeNote("1", "original", time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)),
@@ -169,6 +240,105 @@ func TestFormatGroupedNotes(t *testing.T) {
 	}
 }
 
+func TestFormatGroupedNotesWithCitations(t *testing.T) {
+	notes := []models.Note{
+		makeNote("9abc", "test note", time.Date(2026, 2, 15, 0, 5, 0, 0, time.UTC)),
+	}
+
+	result := FormatGroupedNotesWithCitations(GroupNotes(notes, tokyo), tokyo)
+
+	if !strings.Contains(result, "- [09:05] [note:9abc] test note\n") {
+		t.Errorf("expected note ID citation in output, got:\n%s", result)
+	}
+}
+
+func TestFormatGroupedNotes_ReplyContext(t *testing.T) {
+	host := "remote.example"
+	parent := makeNote("p", "どこのラーメン？\nおすすめある？", time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC))
+	parent.User = &models.UserLite{Username: "alice", Host: &host}
+
+	reply := makeNote("r", "駅前の店がおすすめ", time.Date(2026, 2, 15, 0, 5, 0, 0, time.UTC))
+	reply.ReplyID = strPtr("p")
+	reply.Reply = &parent
+
+	result := FormatGroupedNotes(GroupNotes([]models.Note{reply}, tokyo), tokyo)
+
+	want := "- [09:05] 駅前の店がおすすめ\n  > [Re @alice@remote.example] どこのラーメン？ おすすめある？\n"
+	if !strings.Contains(result, want) {
+		t.Errorf("expected reply context %q, got:\n%s", want, result)
+	}
+}
+
+func TestSplitSharedAttributesOriginalAuthor(t *testing.T) {
+	base := time.Date(2026, 2, 15, 3, 0, 0, 0, time.UTC)
+	original := makeNote("o", "新しいエディタが出た", base.Add(-time.Hour))
+	original.User = &models.UserLite{Username: "bob"}
+
+	renote := models.Note{ID: "rn", CreatedAt: base.Add(time.Hour), RenoteID: strPtr("o"), Renote: &original}
+	quote := makeNote("qt", "これ気になる", base)
+	quote.RenoteID = strPtr("o")
+	quote.Renote = &original
+
+	rest, shared := SplitShared([]models.Note{renote, makeNote("plain", "plain", base), quote}, "シェアしたノート")
+	if len(rest) != 1 || rest[0].ID != "plain" {
+		t.Fatalf("rest = %#v, want only the plain note", rest)
+	}
+	if len(shared) != 1 || len(shared[0].Notes) != 2 || shared[0].Notes[0].ID != "qt" {
+		t.Fatalf("shared = %#v, want quote then renote", shared)
+	}
+
+	result := FormatGroupedNotes(shared, tokyo)
+	for _, want := range []string{
+		"## シェアしたノート\n",
+		"- [12:00] これ気になる\n  > [QT @bob] 新しいエディタが出た\n",
+		"- [13:00] [RN @bob] 新しいエディタが出た\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, result)
+		}
+	}
+
+	if _, shared := SplitShared([]models.Note{makeNote("plain", "plain", base)}, "x"); shared != nil {
+		t.Errorf("expected no shared group, got %#v", shared)
+	}
+}
+
+func TestSplitByChannel(t *testing.T) {
+	base := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
+	inChannel := func(id, channelID string, offset time.Duration) models.Note {
+		n := makeNote(id, id, base.Add(offset))
+		n.ChannelID = strPtr(channelID)
+		return n
+	}
+
+	notes := []models.Note{
+		makeNote("plain", "plain", base),
+		inChannel("cook-2", "cooking", 2*time.Hour),
+		inChannel("game", "games", time.Hour),
+		inChannel("cook-1", "cooking", time.Hour),
+		inChannel("other", "unlisted", time.Hour),
+	}
+
+	rest, groups := SplitByChannel(notes, []Channel{
+		{ID: "cooking", Label: "#料理 チャンネル"},
+		{ID: "music", Label: "#音楽 チャンネル"},
+		{ID: "games", Label: "#ゲーム チャンネル"},
+	})
+
+	if len(rest) != 1 || rest[0].ID != "plain" {
+		t.Fatalf("rest = %#v, want only the plain note", rest)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 channel groups, got %d", len(groups))
+	}
+	if groups[0].Label != "#料理 チャンネル" || groups[0].Notes[0].ID != "cook-1" || groups[0].Notes[1].ID != "cook-2" {
+		t.Errorf("unexpected cooking group: %#v", groups[0])
+	}
+	if groups[1].Label != "#ゲーム チャンネル" || len(groups[1].Notes) != 1 {
+		t.Errorf("unexpected games group: %#v", groups[1])
+	}
+}
+
 func TestFormatGroupedNotes_SkipsEmptyText(t *testing.T) {
 	notes := []models.Note{
 		{
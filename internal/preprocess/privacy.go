@@ -0,0 +1,71 @@
+package preprocess
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+// Privacy levels selectable with privacy.level or --privacy.
+const (
+	PrivacyStrict = "strict"
+	PrivacyNormal = "normal"
+	PrivacyAll    = "all"
+)
+
+// PrivacyPolicy decides which notes may be sent to AI providers and written to output.
+type PrivacyPolicy struct {
+	ExcludeVisibilities []string
+	ExcludeCW           bool
+}
+
+// PrivacyPolicyForLevel returns the preset policy for level. normal excludes
+// direct messages, strict additionally excludes followers-only notes and notes
+// with a CW, and all excludes nothing.
+func PrivacyPolicyForLevel(level string) (PrivacyPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "", PrivacyNormal:
+		return PrivacyPolicy{ExcludeVisibilities: []string{"specified"}}, nil
+	case PrivacyStrict:
+		return PrivacyPolicy{ExcludeVisibilities: []string{"specified", "followers"}, ExcludeCW: true}, nil
+	case PrivacyAll:
+		return PrivacyPolicy{}, nil
+	default:
+		return PrivacyPolicy{}, fmt.Errorf("unsupported privacy level: %s (expected strict, normal or all)", level)
+	}
+}
+
+// Allows reports whether n passes the policy.
+func (p PrivacyPolicy) Allows(n *models.Note) bool {
+	if slices.Contains(p.ExcludeVisibilities, n.Visibility) {
+		return false
+	}
+	if p.ExcludeCW && n.CW != nil {
+		return false
+	}
+	return true
+}
+
+// Apply drops notes the policy excludes. Reply and quote context that the
+// policy excludes is removed, and pure renotes of excluded notes are dropped.
+func (p PrivacyPolicy) Apply(notes []models.Note) []models.Note {
+	filtered := make([]models.Note, 0, len(notes))
+	for _, n := range notes {
+		if !p.Allows(&n) {
+			continue
+		}
+		if n.Renote != nil && !p.Allows(n.Renote) {
+			if !n.IsOriginalNote() {
+				continue
+			}
+			n.Renote = nil
+		}
+		if n.Reply != nil && !p.Allows(n.Reply) {
+			n.Reply = nil
+		}
+		filtered = append(filtered, n)
+	}
+	return filtered
+}
@@ -0,0 +1,71 @@
+package preprocess
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soli0222/diary-cli/internal/models"
+)
+
+func TestPrivacyPolicyForLevel(t *testing.T) {
+	dm := models.Note{ID: "dm", Visibility: "specified"}
+	followers := models.Note{ID: "followers", Visibility: "followers"}
+	cw := models.Note{ID: "cw", Visibility: "public", CW: strPtr("spoiler")}
+	public := models.Note{ID: "public", Visibility: "public"}
+
+	tests := []struct {
+		level string
+		want  map[string]bool
+	}{
+		{level: "all", want: map[string]bool{"dm": true, "followers": true, "cw": true, "public": true}},
+		{level: "", want: map[string]bool{"dm": false, "followers": true, "cw": true, "public": true}},
+		{level: "normal", want: map[string]bool{"dm": false, "followers": true, "cw": true, "public": true}},
+		{level: "Strict", want: map[string]bool{"dm": false, "followers": false, "cw": false, "public": true}},
+	}
+
+	for _, tt := range tests {
+		policy, err := PrivacyPolicyForLevel(tt.level)
+		if err != nil {
+			t.Fatalf("PrivacyPolicyForLevel(%q) error = %v", tt.level, err)
+		}
+		for _, n := range []models.Note{dm, followers, cw, public} {
+			if got := policy.Allows(&n); got != tt.want[n.ID] {
+				t.Errorf("%q: Allows(%s) = %v, want %v", tt.level, n.ID, got, tt.want[n.ID])
+			}
+		}
+	}
+
+	if _, err := PrivacyPolicyForLevel("public"); err == nil {
+		t.Error("expected error for unknown level, got nil")
+	}
+}
+
+func TestPrivacyPolicyApplyStripsContext(t *testing.T) {
+	base := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
+	private := models.Note{ID: "p", Text: strPtr("secret"), Visibility: "followers"}
+
+	reply := makeNote("reply", "reply", base)
+	reply.Visibility = "public"
+	reply.ReplyID = strPtr("p")
+	reply.Reply = &private
+
+	renote := models.Note{ID: "rn", CreatedAt: base, Visibility: "public", RenoteID: strPtr("p"), Renote: &private}
+
+	quote := makeNote("quote", "quote", base)
+	quote.Visibility = "public"
+	quote.RenoteID = strPtr("p")
+	quote.Renote = &private
+
+	policy, _ := PrivacyPolicyForLevel(PrivacyStrict)
+	got := policy.Apply([]models.Note{reply, renote, quote})
+
+	if len(got) != 2 || got[0].ID != "reply" || got[1].ID != "quote" {
+		t.Fatalf("Apply() = %#v, want reply and quote", got)
+	}
+	if got[0].Reply != nil || got[1].Renote != nil {
+		t.Fatalf("expected private context to be removed, got %#v", got)
+	}
+	if reply.Reply == nil {
+		t.Fatal("Apply() must not modify its input")
+	}
+}
@@ -0,0 +1,132 @@
+package preprocess
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Placeholder kinds used by Redactor. Secrets are never restored.
+const (
+	RedactCustom  = "REDACTED"
+	RedactSecret  = "SECRET"
+	RedactEmail   = "EMAIL"
+	RedactPhone   = "PHONE"
+	RedactAddress = "ADDRESS"
+)
+
+var placeholderPattern = regexp.MustCompile(`\[(REDACTED|SECRET|EMAIL|PHONE|ADDRESS)_\d+\]`)
+
+var builtinRedactRules = []redactRule{
+	{kind: RedactSecret, re: regexp.MustCompile(`\b(?:sk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}|AIza[0-9A-Za-z_-]{35}|gh[pousr]_[A-Za-z0-9]{36,}|xox[abprs]-[A-Za-z0-9-]{10,}|AKIA[0-9A-Z]{16}|eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`)},
+	// The optional leading @ lets remote mentions such as @user@misskey.io
+	// match as a whole, so that skip can leave them alone.
+	{kind: RedactEmail, re: regexp.MustCompile(`@?[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), skip: isMention},
+	{kind: RedactPhone, re: regexp.MustCompile(`\+\d{1,3}[-\s]?\d{1,4}[-\s]?\d{1,4}[-\s]?\d{3,4}\b|\b0\d{1,4}-\d{1,4}-\d{4}\b|\b0[789]0\d{8}\b`)},
+	{kind: RedactAddress, re: regexp.MustCompile(`〒?\d{3}-\d{4}|(?:東京都|北海道|(?:京都|大阪)府|\p{Han}{2,3}県)\p{Han}{1,6}[市区町村郡][^\s、。,]{0,20}?\d+(?:[-−‐ー丁目番地号の]+\d+)*`)},
+}
+
+type redactRule struct {
+	kind string
+	re   *regexp.Regexp
+	// skip reports matches that should be left as they are.
+	skip func(string) bool
+}
+
+func isMention(s string) bool {
+	return strings.HasPrefix(s, "@")
+}
+
+// Redactor replaces personal data and secrets with stable placeholders such
+// as [EMAIL_1], so that the same value always maps to the same placeholder.
+type Redactor struct {
+	rules        []redactRule
+	placeholders map[string]string
+	originals    map[string]string
+	counts       map[string]int
+}
+
+// NewRedactor builds a redactor for the built-in rules plus the given words
+// (matched case-insensitively) and regular expressions.
+func NewRedactor(words, patterns []string) (*Redactor, error) {
+	var rules []redactRule
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			rules = append(rules, redactRule{kind: RedactCustom, re: regexp.MustCompile(`(?i)` + regexp.QuoteMeta(w))})
+		}
+	}
+	for i, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction.patterns[%d]: %w", i, err)
+		}
+		rules = append(rules, redactRule{kind: RedactCustom, re: re})
+	}
+
+	return &Redactor{
+		rules:        append(rules, builtinRedactRules...),
+		placeholders: make(map[string]string),
+		originals:    make(map[string]string),
+		counts:       make(map[string]int),
+	}, nil
+}
+
+// Redact replaces every match in text with its placeholder.
+func (r *Redactor) Redact(text string) string {
+	for _, rule := range r.rules {
+		text = replaceOutsidePlaceholders(text, func(s string) string {
+			return rule.re.ReplaceAllStringFunc(s, func(m string) string {
+				if m == "" || rule.skip != nil && rule.skip(m) {
+					return m
+				}
+				return r.placeholder(rule.kind, m)
+			})
+		})
+	}
+	return text
+}
+
+// Restore puts the original values back for every placeholder except secrets.
+func (r *Redactor) Restore(text string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(p string) string {
+		if strings.HasPrefix(p, "["+RedactSecret+"_") {
+			return p
+		}
+		if original, ok := r.originals[p]; ok {
+			return original
+		}
+		return p
+	})
+}
+
+func (r *Redactor) placeholder(kind, value string) string {
+	key := kind + "\x00" + value
+	if p, ok := r.placeholders[key]; ok {
+		return p
+	}
+	r.counts[kind]++
+	p := "[" + kind + "_" + strconv.Itoa(r.counts[kind]) + "]"
+	r.placeholders[key] = p
+	r.originals[p] = value
+	return p
+}
+
+// replaceOutsidePlaceholders applies fn to the parts of text that are not
+// already placeholders, so later rules cannot match inside them.
+func replaceOutsidePlaceholders(text string, fn func(string) string) string {
+	locs := placeholderPattern.FindAllStringIndex(text, -1)
+	if len(locs) == 0 {
+		return fn(text)
+	}
+
+	var sb strings.Builder
+	prev := 0
+	for _, loc := range locs {
+		sb.WriteString(fn(text[prev:loc[0]]))
+		sb.WriteString(text[loc[0]:loc[1]])
+		prev = loc[1]
+	}
+	sb.WriteString(fn(text[prev:]))
+	return sb.String()
+}
@@ -0,0 +1,57 @@
+package preprocess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactorRedactsAndRestores(t *testing.T) {
+	r, err := NewRedactor([]string{"Tanaka"}, []string{`社員番号\d+`})
+	if err != nil {
+		t.Fatalf("NewRedactor() error = %v", err)
+	}
+
+	input := strings.Join([]string{
+		"- [09:00] tanakaさんに alice@example.com から連絡",
+		"- [10:00] 電話は 03-1234-5678 と 090-1234-5678、+81 90 1234 5678",
+		"- [11:00] 〒150-0001 東京都渋谷区神宮前1-2-3 に行った",
+		"- [12:00] うっかり sk-ant-REDACTED を貼った",
+		"- [13:00] 社員番号12345 / alice@example.com / 2026-04-03 15:04",
+	}, "\n")
+
+	got := r.Redact(input)
+
+	for _, leaked := range []string{"tanaka", "alice@example.com", "03-1234-5678", "090-1234-5678", "+81 90 1234 5678", "150-0001", "神宮前", "sk-ant-", "社員番号12345"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("Redact() leaked %q:\n%s", leaked, got)
+		}
+	}
+	for _, want := range []string{"[REDACTED_1]さんに [EMAIL_1] から連絡", "[SECRET_1]", "[REDACTED_2] / [EMAIL_1] / 2026-04-03 15:04"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Redact() missing %q:\n%s", want, got)
+		}
+	}
+
+	restored := r.Restore("[REDACTED_1]さんから [EMAIL_1] にメール。[SECRET_1] は失効させた。[PHONE_9]")
+	if want := "tanakaさんから alice@example.com にメール。[SECRET_1] は失効させた。[PHONE_9]"; restored != want {
+		t.Errorf("Restore() = %q, want %q", restored, want)
+	}
+}
+
+func TestNewRedactorInvalidPattern(t *testing.T) {
+	if _, err := NewRedactor(nil, []string{"("}); err == nil {
+		t.Fatal("expected error for invalid pattern, got nil")
+	}
+}
+
+func TestRedactorKeepsRemoteMentions(t *testing.T) {
+	r, err := NewRedactor(nil, nil)
+	if err != nil {
+		t.Fatalf("NewRedactor() error = %v", err)
+	}
+
+	got := r.Redact("@alice@misskey.io さんと話した。連絡先は bob@example.com")
+	if want := "@alice@misskey.io さんと話した。連絡先は [EMAIL_1]"; got != want {
+		t.Errorf("Redact() = %q, want %q", got, want)
+	}
+}
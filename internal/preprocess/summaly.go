@@ -1,23 +1,46 @@
 package preprocess
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/soli0222/diary-cli/internal/httpretry"
 	"github.com/soli0222/diary-cli/internal/models"
 )
 
 var urlPattern = regexp.MustCompile(`https?://[^\s]+`)
 
+// defaultSummalyConcurrency is the number of links fetched in parallel
+// unless SummalyClient.Concurrency says otherwise.
+const defaultSummalyConcurrency = 4
+
 // SummalyClient fetches link metadata via a Summaly-compatible endpoint.
 type SummalyClient struct {
 	endpoint   string
 	httpClient *http.Client
+	retry      httpretry.Policy
+
+	// Concurrency bounds the number of links fetched at once.
+	Concurrency int
+	// Domains decides which links are summarized.
+	Domains DomainFilter
+	// Cache, when set, keeps summaries across runs.
+	Cache *SummalyCache
+}
+
+// summalyRetry retries briefly: link summaries are optional and a slow
+// endpoint should not hold up the diary.
+var summalyRetry = httpretry.Policy{
+	MaxAttempts: 2,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
 }
 
 // SummalyResponse is the response body from the Summaly service.
@@ -29,6 +52,7 @@ type SummalyResponse struct {
 }
 
 // NewSummalyClientWithEndpoint creates a Summaly client with a custom endpoint.
+// It skips Spotify links until Domains is changed.
 func NewSummalyClientWithEndpoint(endpoint string) *SummalyClient {
 	endpoint = strings.TrimSpace(strings.TrimRight(endpoint, "/"))
 	if endpoint == "" {
@@ -40,12 +64,17 @@ func NewSummalyClientWithEndpoint(endpoint string) *SummalyClient {
 		httpClient: &http.Client{
 			Timeout: 5 * time.Second,
 		},
+		retry:       summalyRetry,
+		Concurrency: defaultSummalyConcurrency,
+		Domains:     DomainFilter{Deny: []string{"spotify.com"}},
 	}
 }
 
-// EnrichNotesWithSummaly appends link summaries to note text for URLs in each note.
-// Spotify links are ignored.
-func EnrichNotesWithSummaly(notes []models.Note, client *SummalyClient) []models.Note {
+// EnrichNotesWithSummaly appends link summaries to note text for URLs in each
+// note, skipping links rejected by client.Domains. Each URL is fetched once,
+// up to client.Concurrency at a time, unless client.Cache already has it.
+// Once ctx is done, no more links are fetched.
+func EnrichNotesWithSummaly(ctx context.Context, notes []models.Note, client *SummalyClient) []models.Note {
 	if client == nil {
 		return notes
 	}
@@ -53,37 +82,31 @@ func EnrichNotesWithSummaly(notes []models.Note, client *SummalyClient) []models
 	enriched := make([]models.Note, len(notes))
 	copy(enriched, notes)
 
-	cache := make(map[string]string)
-
+	targets := make([][]string, len(enriched))
+	var urls []string
+	seen := make(map[string]struct{})
 	for i := range enriched {
 		if enriched[i].Text == nil || *enriched[i].Text == "" {
 			continue
 		}
-
-		text := *enriched[i].Text
-		urls := ExtractSummalyTargets(text)
-		if len(urls) == 0 {
-			continue
-		}
-
-		var lines []string
-		for _, rawURL := range urls {
-			if summary, ok := cache[rawURL]; ok {
-				if summary != "" {
-					lines = append(lines, summary)
-				}
-				continue
+		targets[i] = ExtractSummalyTargets(*enriched[i].Text, client.Domains)
+		for _, rawURL := range targets[i] {
+			if _, ok := seen[rawURL]; !ok {
+				seen[rawURL] = struct{}{}
+				urls = append(urls, rawURL)
 			}
+		}
+	}
+	if len(urls) == 0 {
+		return enriched
+	}
 
-			resp, err := client.Fetch(rawURL)
-			if err != nil {
-				cache[rawURL] = ""
-				continue
-			}
+	summaries := client.fetchSummaries(ctx, urls)
 
-			summary := formatSummalyLine(resp)
-			cache[rawURL] = summary
-			if summary != "" {
+	for i := range enriched {
+		var lines []string
+		for _, rawURL := range targets[i] {
+			if summary := summaries[rawURL]; summary != "" {
 				lines = append(lines, summary)
 			}
 		}
@@ -92,15 +115,65 @@ func EnrichNotesWithSummaly(notes []models.Note, client *SummalyClient) []models
 			continue
 		}
 
-		newText := text + "\n\n[link summary]\n" + strings.Join(lines, "\n")
+		newText := *enriched[i].Text + "\n\n[link summary]\n" + strings.Join(lines, "\n")
 		enriched[i].Text = &newText
 	}
 
 	return enriched
 }
 
-// ExtractSummalyTargets extracts unique non-Spotify URLs from text.
-func ExtractSummalyTargets(text string) []string {
+// fetchSummaries returns the summary line of each URL, taking cached ones from
+// c.Cache and fetching the rest with a pool of c.Concurrency workers. URLs that
+// fail are missing from the result and are not cached.
+func (c *SummalyClient) fetchSummaries(ctx context.Context, urls []string) map[string]string {
+	summaries := make(map[string]string, len(urls))
+	var pending []string
+	for _, rawURL := range urls {
+		if summary, ok := c.Cache.Get(rawURL); ok {
+			summaries[rawURL] = summary
+			continue
+		}
+		pending = append(pending, rawURL)
+	}
+
+	results := make([]*string, len(pending))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(max(c.Concurrency, 1), len(pending)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				resp, err := c.Fetch(ctx, pending[i])
+				if err != nil {
+					continue
+				}
+				summary := formatSummalyLine(resp)
+				results[i] = &summary
+			}
+		}()
+	}
+	for i := range pending {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, rawURL := range pending {
+		if results[i] == nil {
+			continue
+		}
+		summaries[rawURL] = *results[i]
+		c.Cache.Put(rawURL, *results[i])
+	}
+	return summaries
+}
+
+// ExtractSummalyTargets extracts unique URLs allowed by domains from text.
+func ExtractSummalyTargets(text string, domains DomainFilter) []string {
 	matches := urlPattern.FindAllString(text, -1)
 	if len(matches) == 0 {
 		return nil
@@ -110,7 +183,7 @@ func ExtractSummalyTargets(text string) []string {
 	var urls []string
 	for _, m := range matches {
 		normalized := normalizeURLToken(m)
-		if normalized == "" || IsSpotifyURL(normalized) {
+		if normalized == "" || !domains.Allows(normalized) {
 			continue
 		}
 		if _, ok := seen[normalized]; ok {
@@ -123,38 +196,56 @@ func ExtractSummalyTargets(text string) []string {
 	return urls
 }
 
-// IsSpotifyURL returns true if the URL host is spotify.
-func IsSpotifyURL(rawURL string) bool {
+// DomainFilter selects links by host. A domain also matches its subdomains,
+// so "spotify.com" covers "open.spotify.com".
+type DomainFilter struct {
+	// Allow, when non-empty, limits links to these domains.
+	Allow []string
+	// Deny excludes these domains, even when they are allowed.
+	Deny []string
+}
+
+// Allows reports whether the host of rawURL passes the filter.
+func (f DomainFilter) Allows(rawURL string) bool {
 	u, err := url.Parse(rawURL)
 	if err != nil {
 		return false
 	}
 	host := strings.ToLower(u.Hostname())
-	return host == "spotify.com" || host == "www.spotify.com" || host == "open.spotify.com"
+	if matchesDomain(host, f.Deny) {
+		return false
+	}
+	return len(f.Allow) == 0 || matchesDomain(host, f.Allow)
+}
+
+func matchesDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "*"), ".")
+		if domain != "" && (host == domain || strings.HasSuffix(host, "."+domain)) {
+			return true
+		}
+	}
+	return false
 }
 
 // Fetch retrieves metadata for a URL from Summaly.
-func (c *SummalyClient) Fetch(rawURL string) (*SummalyResponse, error) {
+func (c *SummalyClient) Fetch(ctx context.Context, rawURL string) (*SummalyResponse, error) {
 	q := url.QueryEscape(rawURL)
 	endpoint := c.endpoint + "?url=" + q
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.retry.Do(c.httpClient, req)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("summaly: %w", err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("summaly returned status %d", resp.StatusCode)
-	}
-
 	var result SummalyResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
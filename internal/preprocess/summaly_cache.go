@@ -0,0 +1,119 @@
+package preprocess
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SummalyCache is a file-backed URL to summary line cache whose entries
+// expire after a TTL. A nil cache is empty and ignores writes.
+type SummalyCache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]summalyCacheEntry
+	dirty   bool
+}
+
+type summalyCacheEntry struct {
+	Summary   string    `json:"summary"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// LoadSummalyCache reads the cache at path. A missing or corrupt cache
+// file starts an empty cache, which Save then replaces.
+func LoadSummalyCache(path string, ttl time.Duration) (*SummalyCache, error) {
+	c := &SummalyCache{
+		path:    path,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]summalyCacheEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summaly cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		c.entries = make(map[string]summalyCacheEntry)
+		c.dirty = true
+	}
+	return c, nil
+}
+
+// Get returns the cached summary line of rawURL if it has not expired.
+func (c *SummalyCache) Get(rawURL string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[rawURL]
+	if !ok || c.expired(entry) {
+		return "", false
+	}
+	return entry.Summary, true
+}
+
+// Put records the summary line of rawURL.
+func (c *SummalyCache) Put(rawURL, summary string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[rawURL] = summalyCacheEntry{Summary: summary, FetchedAt: c.now()}
+	c.dirty = true
+}
+
+// Save writes the cache back to its file, dropping expired entries. It does
+// nothing when nothing has changed since the cache was loaded.
+func (c *SummalyCache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+	for rawURL, entry := range c.entries {
+		if c.expired(entry) {
+			delete(c.entries, rawURL)
+		}
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode summaly cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create summaly cache directory: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write summaly cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write summaly cache: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
+
+func (c *SummalyCache) expired(entry summalyCacheEntry) bool {
+	return c.now().Sub(entry.FetchedAt) > c.ttl
+}
@@ -2,10 +2,14 @@ package preprocess
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,7 +20,7 @@ func noteTextPtr(s string) *string { return &s }
 
 func TestExtractSummalyTargets(t *testing.T) {
 	text := "ref https://example.com/a, dup https://example.com/a and spotify https://open.spotify.com/track/123 and other https://go.dev/doc"
-	got := ExtractSummalyTargets(text)
+	got := ExtractSummalyTargets(text, DomainFilter{Deny: []string{"spotify.com"}})
 
 	want := []string{"https://example.com/a", "https://go.dev/doc"}
 	if len(got) != len(want) {
@@ -30,20 +34,25 @@ func TestExtractSummalyTargets(t *testing.T) {
 	}
 }
 
-func TestIsSpotifyURL(t *testing.T) {
+func TestDomainFilter(t *testing.T) {
 	tests := []struct {
-		url  string
-		want bool
+		filter DomainFilter
+		url    string
+		want   bool
 	}{
-		{url: "https://open.spotify.com/track/abc", want: true},
-		{url: "https://www.spotify.com/jp/", want: true},
-		{url: "https://spotify.com/", want: true},
-		{url: "https://example.com/spotify.com", want: false},
+		{filter: DomainFilter{Deny: []string{"spotify.com"}}, url: "https://open.spotify.com/track/abc", want: false},
+		{filter: DomainFilter{Deny: []string{"spotify.com"}}, url: "https://www.spotify.com/jp/", want: false},
+		{filter: DomainFilter{Deny: []string{"spotify.com"}}, url: "https://spotify.com/", want: false},
+		{filter: DomainFilter{Deny: []string{"spotify.com"}}, url: "https://example.com/spotify.com", want: true},
+		{filter: DomainFilter{Deny: []string{"spotify.com"}}, url: "https://notspotify.com/", want: true},
+		{filter: DomainFilter{Allow: []string{"*.example.com"}}, url: "https://blog.example.com/a", want: true},
+		{filter: DomainFilter{Allow: []string{"example.com"}}, url: "https://go.dev/doc", want: false},
+		{filter: DomainFilter{Allow: []string{"example.com"}, Deny: []string{"ads.example.com"}}, url: "https://ads.example.com/x", want: false},
 	}
 
 	for _, tt := range tests {
-		if got := IsSpotifyURL(tt.url); got != tt.want {
-			t.Fatalf("IsSpotifyURL(%q) = %v, want %v", tt.url, got, tt.want)
+		if got := tt.filter.Allows(tt.url); got != tt.want {
+			t.Fatalf("%+v.Allows(%q) = %v, want %v", tt.filter, tt.url, got, tt.want)
 		}
 	}
 }
@@ -81,7 +90,7 @@ func TestEnrichNotesWithSummaly(t *testing.T) {
 		},
 	}
 
-	got := EnrichNotesWithSummaly(notes, client)
+	got := EnrichNotesWithSummaly(context.Background(), notes, client)
 
 	if !strings.Contains(*got[0].Text, "[link summary]") {
 		t.Fatalf("first note should contain link summary, got: %q", *got[0].Text)
@@ -114,12 +123,101 @@ func TestEnrichNotesWithSummaly_SkipWhenClientNil(t *testing.T) {
 		},
 	}
 
-	got := EnrichNotesWithSummaly(notes, nil)
+	got := EnrichNotesWithSummaly(context.Background(), notes, nil)
 	if got[0].Text == nil || *got[0].Text != original {
 		t.Fatalf("note text should remain unchanged when client is nil, got: %v", got[0].Text)
 	}
 }
 
+func TestEnrichNotesWithSummaly_StopsWhenCanceled(t *testing.T) {
+	client := NewSummalyClientWithEndpoint("https://summaly.example")
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request to %s", r.URL)
+		return nil, nil
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	original := "check https://example.com/a"
+	notes := []models.Note{{ID: "1", Text: noteTextPtr(original)}}
+	got := EnrichNotesWithSummaly(ctx, notes, client)
+	if got[0].Text == nil || *got[0].Text != original {
+		t.Fatalf("note text should remain unchanged after cancel, got: %v", got[0].Text)
+	}
+}
+
+func TestEnrichNotesWithSummaly_UsesCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "summaly.json")
+	cache, err := LoadSummalyCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("LoadSummalyCache() error = %v", err)
+	}
+	cache.Put("https://example.com/cached", "- Cached page")
+
+	var mu sync.Mutex
+	var fetched []string
+	client := NewSummalyClientWithEndpoint("https://summaly.example")
+	client.Cache = cache
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		raw := r.URL.Query().Get("url")
+		mu.Lock()
+		fetched = append(fetched, raw)
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(fmt.Sprintf(`{"title":"Page for %s"}`, raw))),
+			Header:     make(http.Header),
+		}, nil
+	})}
+
+	notes := []models.Note{
+		{ID: "1", Text: noteTextPtr("https://example.com/cached https://example.com/a")},
+		{ID: "2", Text: noteTextPtr("https://example.com/b https://example.com/c")},
+	}
+	got := EnrichNotesWithSummaly(context.Background(), notes, client)
+
+	if !strings.Contains(*got[0].Text, "- Cached page\n- Page for https://example.com/a") {
+		t.Fatalf("first note = %q", *got[0].Text)
+	}
+	if !strings.Contains(*got[1].Text, "- Page for https://example.com/b\n- Page for https://example.com/c") {
+		t.Fatalf("second note = %q", *got[1].Text)
+	}
+	if len(fetched) != 3 || slices.Contains(fetched, "https://example.com/cached") {
+		t.Fatalf("fetched = %v", fetched)
+	}
+
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	reloaded, err := LoadSummalyCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("LoadSummalyCache() error = %v", err)
+	}
+	if summary, ok := reloaded.Get("https://example.com/b"); !ok || summary != "- Page for https://example.com/b" {
+		t.Fatalf("reloaded Get() = %q, %v", summary, ok)
+	}
+}
+
+func TestSummalyCacheExpires(t *testing.T) {
+	cache, err := LoadSummalyCache(filepath.Join(t.TempDir(), "summaly.json"), time.Hour)
+	if err != nil {
+		t.Fatalf("LoadSummalyCache() error = %v", err)
+	}
+	now := time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	cache.Put("https://example.com/a", "- A")
+
+	now = now.Add(59 * time.Minute)
+	if _, ok := cache.Get("https://example.com/a"); !ok {
+		t.Fatal("Get() before TTL = false")
+	}
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.Get("https://example.com/a"); ok {
+		t.Fatal("Get() after TTL = true")
+	}
+}
+
 type roundTripFunc func(*http.Request) (*http.Response, error)
 
 func (fn roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {